@@ -0,0 +1,620 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	lsv1alpha1 "github.com/gardener/landscaper/apis/core/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InfrastructureProvider describes the infrastructure provider that hosts the seed/garden cluster.
+type InfrastructureProvider string
+
+const (
+	// InfrastructureProviderAWS is the AWS infrastructure provider.
+	InfrastructureProviderAWS InfrastructureProvider = "aws"
+	// InfrastructureProviderGCP is the GCP infrastructure provider.
+	InfrastructureProviderGCP InfrastructureProvider = "gcp"
+	// InfrastructureProviderAzure is the Azure infrastructure provider.
+	InfrastructureProviderAzure InfrastructureProvider = "azure"
+	// InfrastructureProviderAlicloud is the Alicloud infrastructure provider.
+	InfrastructureProviderAlicloud InfrastructureProvider = "alicloud"
+)
+
+// Imports defines the structure for the input parameters used by the virtual-garden component.
+type Imports struct {
+	// Cluster is the target cluster that hosts the virtual garden.
+	Cluster lsv1alpha1.Target `json:"cluster"`
+	// HostingCluster contains the configuration of the hosting cluster.
+	HostingCluster HostingCluster `json:"hostingCluster"`
+	// VirtualGarden contains the configuration for the virtual garden cluster itself.
+	VirtualGarden VirtualGarden `json:"virtualGarden"`
+	// Credentials contains the set of credentials that can be referenced by name from other import fields.
+	Credentials map[string]Credentials `json:"credentials,omitempty"`
+}
+
+// HostingCluster contains the configuration of the cluster hosting the virtual garden control plane.
+type HostingCluster struct {
+	// Kubeconfig is the kubeconfig of the hosting cluster.
+	Kubeconfig string `json:"kubeconfig"`
+	// Namespace is the namespace in the hosting cluster into which the virtual garden control plane is deployed.
+	Namespace string `json:"namespace"`
+	// InfrastructureProvider is the infrastructure provider the hosting cluster runs on.
+	InfrastructureProvider InfrastructureProvider `json:"infrastructureProvider"`
+}
+
+// VirtualGarden contains the configuration for the virtual garden cluster.
+type VirtualGarden struct {
+	// ETCD contains the configuration for the etcd of the virtual garden.
+	ETCD *ETCD `json:"etcd,omitempty"`
+	// KubeAPIServer contains the configuration for the kube-apiserver of the virtual garden.
+	KubeAPIServer *KubeAPIServer `json:"kubeAPIServer,omitempty"`
+	// KubeControllerManager contains the configuration for the kube-controller-manager of the virtual garden.
+	KubeControllerManager *KubeControllerManager `json:"kubeControllerManager,omitempty"`
+	// GardenerControllerManager contains the configuration for the gardener-controller-manager of the virtual
+	// garden.
+	GardenerControllerManager *GardenerControllerManager `json:"gardenerControllerManager,omitempty"`
+	// PriorityClassName is the name of the priority class that is assigned to all virtual garden control plane pods.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// DeleteNamespace specifies whether the hosting cluster namespace shall be deleted when the virtual garden is deleted.
+	DeleteNamespace bool `json:"deleteNamespace,omitempty"`
+}
+
+// ETCD contains the configuration for the etcd of the virtual garden.
+type ETCD struct {
+	// StorageClassName is the name of the storage class used for the etcd volumes.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Backup contains the configuration for backing up the etcd data to an object store.
+	Backup *ETCDBackup `json:"backup,omitempty"`
+}
+
+// ETCDBackup contains the configuration for the etcd backup of the virtual garden.
+type ETCDBackup struct {
+	// InfrastructureProvider is the infrastructure provider that hosts the backup bucket.
+	InfrastructureProvider InfrastructureProvider `json:"infrastructureProvider"`
+	// Region is the region in which the backup bucket is located.
+	Region string `json:"region"`
+	// BucketName is the name of the bucket that stores the etcd backups. Used by AWS, GCP and Alicloud.
+	BucketName string `json:"bucketName,omitempty"`
+	// StorageAccount is the name of the Azure storage account that stores the etcd backups.
+	StorageAccount string `json:"storageAccount,omitempty"`
+	// ContainerName is the name of the Azure blob container that stores the etcd backups.
+	ContainerName string `json:"containerName,omitempty"`
+	// ResourceGroup is the name of the Azure resource group that StorageAccount is (or, if ManageBucket is set,
+	// will be) created in. Only relevant for the Azure provider.
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	// OSSEndpoint is the Alicloud OSS endpoint that hosts BucketName. Only relevant for the Alicloud provider.
+	OSSEndpoint string `json:"ossEndpoint,omitempty"`
+	// ManageBucket specifies whether the backup bucket is provisioned and destroyed by this component via
+	// Terraform, rather than referencing a pre-existing bucket. When true, BucketName (or the Azure
+	// StorageAccount/ContainerName pair) is optional.
+	ManageBucket bool `json:"manageBucket,omitempty"`
+	// CredentialsRef references an entry in Imports.Credentials that is used to access the backup bucket.
+	CredentialsRef string `json:"credentialsRef"`
+}
+
+// Credentials contains credential data for a given infrastructure provider.
+type Credentials struct {
+	// Type is the infrastructure provider the credentials belong to.
+	Type InfrastructureProvider `json:"type"`
+	// Data contains the actual credential key/value pairs.
+	Data map[string]string `json:"data"`
+}
+
+// SNIEntry contains the configuration for a single SNI certificate served by the kube-apiserver via a
+// --tls-sni-cert-key flag. The kube-apiserver accepts this flag multiple times, once per entry.
+type SNIEntry struct {
+	// SecretName is the name of the secret containing the TLS certificate and key.
+	SecretName string `json:"secretName"`
+	// Hostnames are the hostnames for which the certificate referenced by SecretName shall be served.
+	Hostnames []string `json:"hostnames"`
+	// CertManager, if set, reconciles a cert-manager.io Certificate producing SecretName instead of expecting
+	// the secret to already exist.
+	CertManager *SNICertManager `json:"certManager,omitempty"`
+}
+
+// SNICertManager configures the cert-manager.io Certificate reconciled for an SNIEntry.
+type SNICertManager struct {
+	// IssuerRef references the cert-manager Issuer or ClusterIssuer that shall sign the certificate.
+	IssuerRef SNICertManagerIssuerRef `json:"issuerRef"`
+	// DNSNames are the DNS names (SANs) requested on the certificate. Defaults to the entry's Hostnames if unset.
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// SNICertManagerIssuerRef references a cert-manager Issuer or ClusterIssuer.
+type SNICertManagerIssuerRef struct {
+	// Name is the name of the Issuer or ClusterIssuer.
+	Name string `json:"name"`
+	// Kind is the kind of the referenced resource, e.g. "Issuer" or "ClusterIssuer". Defaults to "Issuer".
+	Kind string `json:"kind,omitempty"`
+	// Group is the API group of the referenced resource. Defaults to "cert-manager.io".
+	Group string `json:"group,omitempty"`
+}
+
+// KubeAPIServerExposure contains the configuration that controls how the kube-apiserver is exposed.
+type KubeAPIServerExposure struct {
+	// SNI contains the SNI configuration for the kube-apiserver.
+	SNI *SNI `json:"sni,omitempty"`
+}
+
+// SNI contains the configuration for the SNI DNS entry pointing to the kube-apiserver's load balancer.
+type SNI struct {
+	// Hostnames are the hostnames for which the SNI DNS entry is created.
+	Hostnames []string `json:"hostnames"`
+	// DNSClass is the `dns.gardener.cloud/class` used for the DNS entry.
+	DNSClass *string `json:"dnsClass,omitempty"`
+	// TTL is the time-to-live in seconds for the DNS entry.
+	TTL *int32 `json:"ttl,omitempty"`
+}
+
+// GardenerControlplane contains the configuration of the gardener-apiserver admission webhooks.
+type GardenerControlplane struct {
+	// ValidatingWebhookEnabled specifies whether the validating webhook kubeconfig shall be deployed.
+	ValidatingWebhookEnabled bool `json:"validatingWebhookEnabled,omitempty"`
+	// MutatingWebhookEnabled specifies whether the mutating webhook kubeconfig shall be deployed.
+	MutatingWebhookEnabled bool `json:"mutatingWebhookEnabled,omitempty"`
+	// ValidatingWebhook contains the configuration for the validating webhook.
+	ValidatingWebhook GardenerControlplaneWebhook `json:"validatingWebhook,omitempty"`
+	// MutatingWebhook contains the configuration for the mutating webhook.
+	MutatingWebhook GardenerControlplaneWebhook `json:"mutatingWebhook,omitempty"`
+}
+
+// GardenerControlplaneWebhook contains the configuration for a single gardener-apiserver admission webhook.
+type GardenerControlplaneWebhook struct {
+	// Server is the URL at which the kube-apiserver reaches the gardener-apiserver admission webhook. The
+	// kubeconfig's certificate-authority-data is generated and rotated automatically; Server only needs to name
+	// the endpoint.
+	Server string `json:"server,omitempty"`
+	// Token contains the configuration for the projected service account token used by the webhook client.
+	Token ProjectedServiceAccountToken `json:"token,omitempty"`
+}
+
+// ProjectedServiceAccountToken contains the configuration for a projected service account token volume.
+type ProjectedServiceAccountToken struct {
+	// Enabled specifies whether a projected service account token shall be used instead of a static credential.
+	Enabled bool `json:"enabled,omitempty"`
+	// Audience is the intended audience of the token.
+	Audience []string `json:"audience,omitempty"`
+	// ExpirationSeconds is the requested validity duration of the token.
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+}
+
+// AuditWebhookConfig contains the configuration for the kube-apiserver audit webhook backend.
+type AuditWebhookConfig struct {
+	// Config is the raw audit webhook kubeconfig.
+	Config string `json:"config,omitempty"`
+}
+
+// Audit contains the full audit-logging configuration for the kube-apiserver: the audit policy plus the log
+// file and/or webhook backends that consume it. See the upstream kube-apiserver auditing documentation.
+type Audit struct {
+	// Policy is the structured audit policy. If nil, a minimal default policy is rendered.
+	Policy *AuditPolicy `json:"policy,omitempty"`
+	// LogBackend configures the `--audit-log-*` flags and, optionally, a sidecar that ships the log file
+	// elsewhere.
+	LogBackend *AuditLogBackend `json:"logBackend,omitempty"`
+	// WebhookBackend configures the `--audit-webhook-*` flags.
+	WebhookBackend *AuditWebhookBackend `json:"webhookBackend,omitempty"`
+}
+
+// AuditPolicy is a structured representation of the audit policy rendered into the audit-policy ConfigMap.
+type AuditPolicy struct {
+	// Rules is the ordered list of audit policy rules, evaluated top to bottom like the upstream
+	// audit.k8s.io policy.
+	Rules []AuditPolicyRule `json:"rules"`
+}
+
+// AuditPolicyRule is a single rule of an AuditPolicy.
+type AuditPolicyRule struct {
+	// Level is the audit level for requests matching this rule (e.g. None, Metadata, Request, RequestResponse).
+	Level string `json:"level"`
+	// Resources restricts the rule to the given API groups/resources. Empty matches all resources.
+	Resources []GroupResources `json:"resources,omitempty"`
+	// Namespaces restricts the rule to the given namespaces. Empty matches all namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Verbs restricts the rule to the given verbs. Empty matches all verbs.
+	Verbs []string `json:"verbs,omitempty"`
+	// OmitStages lists the audit stages that should not be recorded for this rule.
+	OmitStages []string `json:"omitStages,omitempty"`
+}
+
+// GroupResources restricts an AuditPolicyRule to a set of resources within an API group.
+type GroupResources struct {
+	// Group is the API group. The empty string selects the core group.
+	Group string `json:"group,omitempty"`
+	// Resources is the list of resource names within Group.
+	Resources []string `json:"resources,omitempty"`
+}
+
+// AuditLogBackend configures the kube-apiserver's `--audit-log-*` flags.
+type AuditLogBackend struct {
+	// MaxSize is the value for --audit-log-maxsize (megabytes).
+	MaxSize *int32 `json:"maxSize,omitempty"`
+	// MaxBackup is the value for --audit-log-maxbackup.
+	MaxBackup *int32 `json:"maxBackup,omitempty"`
+	// MaxAge is the value for --audit-log-maxage (days).
+	MaxAge *int32 `json:"maxAge,omitempty"`
+	// Format is the value for --audit-log-format (json or legacy).
+	Format string `json:"format,omitempty"`
+	// Sidecar, when set, ships the audit log file via the given container image instead of leaving it on the
+	// volume.
+	Sidecar *AuditLogSidecar `json:"sidecar,omitempty"`
+}
+
+// AuditLogSidecar configures a container shipping the kube-apiserver's audit log file elsewhere.
+type AuditLogSidecar struct {
+	// Image is the container image used for the sidecar.
+	Image string `json:"image"`
+}
+
+// AuditWebhookBackend configures the kube-apiserver's `--audit-webhook-*` flags.
+type AuditWebhookBackend struct {
+	// Config is the raw audit webhook kubeconfig.
+	Config string `json:"config,omitempty"`
+	// BatchMaxSize is the value for --audit-webhook-batch-max-size.
+	BatchMaxSize string `json:"batchMaxSize,omitempty"`
+	// BatchBufferSize is the value for --audit-webhook-batch-buffer-size.
+	BatchBufferSize string `json:"batchBufferSize,omitempty"`
+	// BatchMaxWait is the value for --audit-webhook-batch-max-wait.
+	BatchMaxWait string `json:"batchMaxWait,omitempty"`
+	// BatchThrottleQPS is the value for --audit-webhook-batch-throttle-qps.
+	BatchThrottleQPS string `json:"batchThrottleQps,omitempty"`
+	// Mode is the value for --audit-webhook-mode (batch or blocking).
+	Mode string `json:"mode,omitempty"`
+	// Token contains the configuration for the projected service account token used to authenticate against
+	// the audit webhook backend, instead of the static credential embedded in Config.
+	Token ProjectedServiceAccountToken `json:"token,omitempty"`
+}
+
+// SeedAuthorizer contains the configuration for the seed-authorizer webhook.
+type SeedAuthorizer struct {
+	// Enabled specifies whether the seed-authorizer webhook is used as an authorization webhook.
+	Enabled bool `json:"enabled,omitempty"`
+	// Token contains the configuration for the projected service account token used to authenticate against
+	// the seed-authorizer webhook.
+	Token ProjectedServiceAccountToken `json:"token,omitempty"`
+}
+
+// OidcWebhookAuthenticator contains the configuration for the OIDC webhook authenticator.
+type OidcWebhookAuthenticator struct {
+	// Enabled specifies whether the OIDC webhook authenticator is used as an authentication token webhook.
+	Enabled bool `json:"enabled,omitempty"`
+	// Kubeconfig is the kubeconfig used to reach the webhook authenticator, stored in a secret and mounted
+	// alongside the kube-apiserver, analogous to the admission webhook kubeconfig.
+	Kubeconfig []byte `json:"kubeconfig,omitempty"`
+	// Token contains the configuration for the projected service account token used to authenticate against
+	// the OIDC webhook authenticator.
+	Token ProjectedServiceAccountToken `json:"token,omitempty"`
+}
+
+// Authentication configures how the kube-apiserver authenticates client requests, on top of the webhook token
+// authenticator configured via OidcWebhookAuthenticator.
+type Authentication struct {
+	// OIDC configures the kube-apiserver's built-in OIDC authenticator (the --oidc-* flags). Takes precedence
+	// over the deprecated top-level OidcIssuerURL.
+	OIDC *OIDCAuthentication `json:"oidc,omitempty"`
+	// StructuredConfig, if set, is rendered into a secret and passed via --authentication-config, the newer
+	// structured file format covering multiple JWT authenticators. Mutually exclusive with OIDC.
+	StructuredConfig *AuthenticationConfig `json:"structuredConfig,omitempty"`
+	// BasicAuth enables the deprecated admin/system:masters basic-auth credential (removed upstream as of
+	// Kubernetes 1.22). Defaults to false, and is ignored if OIDC or StructuredConfig is set.
+	BasicAuth bool `json:"basicAuth,omitempty"`
+}
+
+// OIDCAuthentication configures the kube-apiserver's built-in OIDC authenticator.
+type OIDCAuthentication struct {
+	// IssuerURL is the value for --oidc-issuer-url.
+	IssuerURL string `json:"issuerURL"`
+	// ClientID is the value for --oidc-client-id.
+	ClientID string `json:"clientID"`
+	// UsernameClaim is the value for --oidc-username-claim. Defaults to "email".
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// GroupsClaim is the value for --oidc-groups-claim. Defaults to "groups".
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// CABundle is the PEM-encoded CA bundle for --oidc-ca-file, stored in a secret. If unset, the issuer's
+	// certificate must chain to the host's CA trust store.
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// AuthenticationConfig holds the raw content of an AuthenticationConfiguration file
+// (apiserver.config.k8s.io/v1beta1), passed via --authentication-config instead of the --oidc-* flags.
+type AuthenticationConfig struct {
+	// Config is the raw AuthenticationConfiguration file content.
+	Config string `json:"config"`
+}
+
+// HVPA contains the configuration for the HVPA of a component.
+type HVPA struct {
+	// MaintenanceTimeWindow restricts scaling decisions to a particular daily time window.
+	MaintenanceTimeWindow *string `json:"maintenanceTimeWindow,omitempty"`
+}
+
+// HorizontalPodAutoscaler contains the configuration for the kube-apiserver's HorizontalPodAutoscaler.
+type HorizontalPodAutoscaler struct {
+	// MinReplicas is the minimum number of kube-apiserver replicas.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the maximum number of kube-apiserver replicas.
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+}
+
+// WorkloadPolicy configures the availability and resource-scaling policy enforced for a managed component's
+// Deployment via a PodDisruptionBudget and a VerticalPodAutoscaler.
+type WorkloadPolicy struct {
+	// MinAvailable is the minimum number of available replicas enforced by the PodDisruptionBudget. Mutually
+	// exclusive with MaxUnavailable; defaults to 1 if neither is set.
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the maximum number of unavailable replicas enforced by the PodDisruptionBudget.
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+	// VPAUpdateMode is the update mode for the VerticalPodAutoscaler, e.g. "Auto" or "Off". Defaults to "Auto".
+	VPAUpdateMode string `json:"vpaUpdateMode,omitempty"`
+	// VPAMinAllowed is the minimum container resources allowed by the VerticalPodAutoscaler.
+	VPAMinAllowed corev1.ResourceList `json:"vpaMinAllowed,omitempty"`
+	// VPAMaxAllowed is the maximum container resources allowed by the VerticalPodAutoscaler.
+	VPAMaxAllowed corev1.ResourceList `json:"vpaMaxAllowed,omitempty"`
+}
+
+// KubeAPIServer contains the configuration for the kube-apiserver of the virtual garden.
+type KubeAPIServer struct {
+	// Replicas is the number of kube-apiserver replicas.
+	Replicas int `json:"replicas,omitempty"`
+	// SNI contains the SNI certificate configuration for the kube-apiserver. One --tls-sni-cert-key flag is
+	// emitted per entry.
+	SNI []SNIEntry `json:"sni,omitempty"`
+	// Exposure contains the configuration that controls how the kube-apiserver is exposed.
+	Exposure *KubeAPIServerExposure `json:"exposure,omitempty"`
+	// DnsAccessDomain is the domain under which the virtual garden's service account issuer is reachable.
+	DnsAccessDomain string `json:"dnsAccessDomain"`
+	// GardenerControlplane contains the configuration of the gardener-apiserver admission webhooks.
+	GardenerControlplane GardenerControlplane `json:"gardenerControlplane,omitempty"`
+	// AuditWebhookConfig contains the configuration for the kube-apiserver audit webhook backend.
+	AuditWebhookConfig AuditWebhookConfig `json:"auditWebhookConfig,omitempty"`
+	// AuditWebhookBatchMaxSize is the value for the --audit-webhook-batch-max-size flag.
+	//
+	// Deprecated: set VirtualGarden.KubeAPIServer.Audit.WebhookBackend.BatchMaxSize instead.
+	AuditWebhookBatchMaxSize string `json:"auditWebhookBatchMaxSize,omitempty"`
+	// Audit contains the full audit-logging configuration (policy plus log-file/webhook backends). It takes
+	// precedence over AuditWebhookConfig/AuditWebhookBatchMaxSize above.
+	Audit *Audit `json:"audit,omitempty"`
+	// SeedAuthorizer contains the configuration for the seed-authorizer webhook.
+	SeedAuthorizer SeedAuthorizer `json:"seedAuthorizer,omitempty"`
+	// OidcWebhookAuthenticator contains the configuration for the OIDC webhook authenticator.
+	OidcWebhookAuthenticator OidcWebhookAuthenticator `json:"oidcWebhookAuthenticator,omitempty"`
+	// HVPAEnabled specifies whether an HVPA object shall be deployed for the kube-apiserver.
+	HVPAEnabled bool `json:"hvpaEnabled,omitempty"`
+	// HVPA contains the configuration for the kube-apiserver's HVPA.
+	HVPA *HVPA `json:"hvpa,omitempty"`
+	// EventTTL is the value for the --event-ttl flag.
+	EventTTL *string `json:"eventTTL,omitempty"`
+	// OidcIssuerURL is the value for the --oidc-issuer-url flag.
+	//
+	// Deprecated: set Authentication.OIDC.IssuerURL instead.
+	OidcIssuerURL *string `json:"oidcIssuerURL,omitempty"`
+	// Authentication configures OIDC/structured authentication and whether the deprecated basic-auth
+	// credential is created. Basic auth defaults to off; if unset entirely, authentication falls back to the
+	// deprecated OidcIssuerURL (if set).
+	Authentication *Authentication `json:"authentication,omitempty"`
+	// Profiling specifies whether profiling endpoints are enabled.
+	Profiling bool `json:"profiling,omitempty"`
+	// AdditionalVolumeMounts is a list of additional volume mounts for the kube-apiserver container.
+	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+	// AdditionalVolumes is a list of additional volumes for the kube-apiserver pod.
+	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+	// HorizontalPodAutoscaler contains the configuration for the kube-apiserver's HorizontalPodAutoscaler.
+	HorizontalPodAutoscaler *HorizontalPodAutoscaler `json:"horizontalPodAutoscaler,omitempty"`
+	// MaxMutatingRequestsInflight is the value for the --max-mutating-requests-inflight flag.
+	MaxMutatingRequestsInflight *int `json:"maxMutatingRequestsInflight,omitempty"`
+	// MaxRequestsInflight is the value for the --max-requests-inflight flag.
+	MaxRequestsInflight *int `json:"maxRequestsInflight,omitempty"`
+	// ExtraArgs is a map of additional or overriding kube-apiserver command-line flags, keyed by flag name
+	// (without leading dashes). An entry here always wins over the same flag computed from the fields above.
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+	// FeatureGates is a map of kube-apiserver feature gates, rendered into a single sorted
+	// --feature-gates=<name>=<bool>,... flag.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// WorkloadPolicy configures the kube-apiserver's PodDisruptionBudget and VerticalPodAutoscaler.
+	WorkloadPolicy *WorkloadPolicy `json:"workloadPolicy,omitempty"`
+	// HostCACerts controls how the host's CA trust store is made available to the kube-apiserver container for
+	// validating outgoing TLS connections (e.g. to webhooks or OIDC issuers). If unset, it defaults to
+	// HostCACertsModeBundleAllHostPaths for backwards compatibility.
+	//
+	// Deprecated: the HostCACertsModeBundleAllHostPaths default mounts every well-known trust-store hostPath
+	// unconditionally; set an explicit mode instead.
+	HostCACerts *HostCACerts `json:"hostCACerts,omitempty"`
+	// EncryptionConfig configures the kube-apiserver's at-rest encryption providers. If unset, a single
+	// AES-CBC provider for secrets (plus an identity fallback) is generated, matching the historical default.
+	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
+	// RotationConfig triggers rotation of the kube-apiserver's managed secrets (basic-auth credentials,
+	// encryption config, service-account signing key). Each field is an opaque seed value; bumping it causes a
+	// new, hash-suffixed version of the corresponding secret to be generated via secretsmanager, while the
+	// previous version is retained for GracePeriod before garbage collection.
+	RotationConfig *RotationConfig `json:"rotationConfig,omitempty"`
+	// WebhookCertificateRenewalWindow is how long before expiry the self-signed CA and serving certificates
+	// generated for the gardener-apiserver admission webhook kubeconfigs are rotated. Defaults to
+	// webhookcerts.DefaultRenewalWindow (30 days) if unset.
+	WebhookCertificateRenewalWindow *string `json:"webhookCertificateRenewalWindow,omitempty"`
+}
+
+// RotationConfig triggers rotation of the kube-apiserver's managed secrets. Bumping a field (e.g. incrementing a
+// counter or setting a timestamp) forces a new version of the corresponding secret to be generated.
+type RotationConfig struct {
+	// BasicAuth rotates the kube-apiserver's basic-auth credentials.
+	BasicAuth string `json:"basicAuth,omitempty"`
+	// EncryptionKey rotates the kube-apiserver's at-rest encryption key.
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+	// ServiceAccountKey rotates the kube-apiserver's service-account signing key.
+	ServiceAccountKey string `json:"serviceAccountKey,omitempty"`
+	// GracePeriod is how long a superseded secret version is retained, as a Go duration string (e.g. "168h"),
+	// before a later reconcile garbage collects it; Delete removes every version regardless. Defaults to
+	// secretsmanager.DefaultGracePeriod if empty.
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+// EncryptionConfig configures the resources and providers rendered into the kube-apiserver's
+// EncryptionConfiguration.
+type EncryptionConfig struct {
+	// Resources is the ordered list of resource-to-provider mappings, mirroring the upstream
+	// EncryptionConfiguration's `resources` list.
+	Resources []EncryptionResourceConfig `json:"resources,omitempty"`
+	// RotateNow requests that a fresh primary key be generated for every aescbc/aesgcm/secretbox provider on
+	// the next reconcile. The previous key(s) are retained below the new one so existing data stays readable
+	// until it is rewritten.
+	RotateNow bool `json:"rotateNow,omitempty"`
+}
+
+// EncryptionResourceConfig maps a set of API resources to the ordered list of providers used to en-/decrypt them.
+type EncryptionResourceConfig struct {
+	// Resources is the list of resources (e.g. "secrets", "configmaps") this entry applies to.
+	Resources []string `json:"resources"`
+	// Providers is the ordered list of providers. The first entry is used for encryption; all entries are tried
+	// in order for decryption.
+	Providers []EncryptionProviderConfig `json:"providers"`
+}
+
+// EncryptionProviderConfig configures a single entry of an EncryptionResourceConfig's provider list. Exactly
+// one of its fields should be set.
+type EncryptionProviderConfig struct {
+	// AESCBC, if set, uses AES-CBC with a generated and rotated key.
+	AESCBC *EncryptionKeyProviderConfig `json:"aescbc,omitempty"`
+	// AESGCM, if set, uses AES-GCM with a generated and rotated key.
+	AESGCM *EncryptionKeyProviderConfig `json:"aesgcm,omitempty"`
+	// Secretbox, if set, uses XSalsa20Poly1305 (NaCl secretbox) with a generated and rotated key.
+	Secretbox *EncryptionKeyProviderConfig `json:"secretbox,omitempty"`
+	// Identity, if set, stores the resource unencrypted. Typically used as the last, fallback provider.
+	Identity *EncryptionIdentityProviderConfig `json:"identity,omitempty"`
+	// KMS, if set, delegates key management to an external KMS plugin.
+	KMS *EncryptionKMSProviderConfig `json:"kms,omitempty"`
+}
+
+// EncryptionKeyProviderConfig is the (currently empty) configuration for a generated-key provider (aescbc,
+// aesgcm, secretbox). It exists so the field's presence/absence on EncryptionProviderConfig selects the provider.
+type EncryptionKeyProviderConfig struct{}
+
+// EncryptionIdentityProviderConfig is the (currently empty) configuration for the identity provider.
+type EncryptionIdentityProviderConfig struct{}
+
+// EncryptionKMSProviderConfig configures a KMS provider entry, see the upstream KMSConfiguration.
+type EncryptionKMSProviderConfig struct {
+	// Name is the name of the KMS plugin.
+	Name string `json:"name"`
+	// Endpoint is the gRPC unix socket endpoint of the KMS plugin, e.g. unix:///var/run/kms-plugin/kms.sock.
+	Endpoint string `json:"endpoint"`
+	// CacheSize is the number of data encryption keys cached in memory.
+	CacheSize *int32 `json:"cacheSize,omitempty"`
+	// Timeout is the duration the client waits for an RPC to complete before timing out, e.g. "3s".
+	Timeout *string `json:"timeout,omitempty"`
+	// Image, if set, runs the KMS plugin as a sidecar container using this image alongside the kube-apiserver.
+	// If unset, the KMS plugin is assumed to be provided externally (e.g. as a DaemonSet on the host).
+	Image string `json:"image,omitempty"`
+}
+
+// HostCACertsMode selects how the kube-apiserver container obtains the host's CA trust store.
+type HostCACertsMode string
+
+const (
+	// HostCACertsModeAuto mounts every well-known trust-store hostPath, like HostCACertsModeBundleAllHostPaths,
+	// and adds a required nodeAffinity term restricting scheduling to nodes labeled kubernetes.io/os=linux. It
+	// does not select a single hostPath for the Node's OS.
+	HostCACertsModeAuto HostCACertsMode = "Auto"
+	// HostCACertsModeDebian mounts only the Debian/Ubuntu trust-store hostPath (/etc/ssl).
+	HostCACertsModeDebian HostCACertsMode = "Debian"
+	// HostCACertsModeFedora mounts only the Fedora trust-store hostPath (/etc/pki/tls).
+	HostCACertsModeFedora HostCACertsMode = "Fedora"
+	// HostCACertsModeCentos mounts only the CentOS trust-store hostPath (/etc/pki/ca-trust/extracted/pem).
+	HostCACertsModeCentos HostCACertsMode = "Centos"
+	// HostCACertsModeNone mounts no host trust store at all.
+	HostCACertsModeNone HostCACertsMode = "None"
+	// HostCACertsModeBundle mounts a single ca-certificates.crt from the referenced Secret or ConfigMap as a
+	// plain volume, without touching the host filesystem.
+	HostCACertsModeBundle HostCACertsMode = "Bundle"
+	// HostCACertsModeBundleAllHostPaths mounts every well-known trust-store hostPath unconditionally, since the
+	// Node OS is not known. This is the pre-existing, deprecated default behavior.
+	HostCACertsModeBundleAllHostPaths HostCACertsMode = "BundleAllHostPaths"
+)
+
+// HostCACerts controls how the host's CA trust store is made available to the kube-apiserver container.
+type HostCACerts struct {
+	// Mode selects the strategy used to provide the CA trust store. Defaults to HostCACertsModeBundleAllHostPaths.
+	Mode HostCACertsMode `json:"mode,omitempty"`
+	// Bundle references the Secret or ConfigMap containing the ca-certificates.crt used when Mode is
+	// HostCACertsModeBundle.
+	Bundle *HostCACertsBundle `json:"bundle,omitempty"`
+}
+
+// HostCACertsBundle references the object containing a ca-certificates.crt bundle.
+type HostCACertsBundle struct {
+	// SecretName is the name of the Secret containing the ca-certificates.crt key. Mutually exclusive with
+	// ConfigMapName.
+	SecretName string `json:"secretName,omitempty"`
+	// ConfigMapName is the name of the ConfigMap containing the ca-certificates.crt key. Mutually exclusive
+	// with SecretName.
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// GetMaxMutatingRequestsInflight returns the configured value for --max-mutating-requests-inflight, or the given default.
+func (k *KubeAPIServer) GetMaxMutatingRequestsInflight(def int) int {
+	if k.MaxMutatingRequestsInflight == nil {
+		return def
+	}
+	return *k.MaxMutatingRequestsInflight
+}
+
+// GetMaxRequestsInflight returns the configured value for --max-requests-inflight, or the given default.
+func (k *KubeAPIServer) GetMaxRequestsInflight(def int) int {
+	if k.MaxRequestsInflight == nil {
+		return def
+	}
+	return *k.MaxRequestsInflight
+}
+
+// KubeControllerManager contains the configuration for the kube-controller-manager of the virtual garden.
+type KubeControllerManager struct {
+	// Replicas is the number of kube-controller-manager replicas.
+	Replicas int `json:"replicas,omitempty"`
+	// ExtraArgs is a map of additional or overriding kube-controller-manager command-line flags, keyed by flag
+	// name (without leading dashes). An entry here always wins over the same flag computed from the fields above.
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+	// FeatureGates is a map of kube-controller-manager feature gates, rendered into a single sorted
+	// --feature-gates=<name>=<bool>,... flag.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// WorkloadPolicy configures the kube-controller-manager's PodDisruptionBudget and VerticalPodAutoscaler.
+	WorkloadPolicy *WorkloadPolicy `json:"workloadPolicy,omitempty"`
+}
+
+// GardenerControllerManager contains the configuration for the gardener-controller-manager of the virtual garden.
+type GardenerControllerManager struct {
+	// Image is the image reference for the gardener-controller-manager.
+	Image string `json:"image,omitempty"`
+	// Replicas is the number of gardener-controller-manager replicas.
+	Replicas int `json:"replicas,omitempty"`
+	// FeatureGates is a map of gardener-controller-manager feature gates, rendered into the component config's
+	// featureGates map.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// LogLevel is the gardener-controller-manager's log level, e.g. "info", "debug", "error".
+	LogLevel string `json:"logLevel,omitempty"`
+	// WorkloadPolicy configures the gardener-controller-manager's PodDisruptionBudget and VerticalPodAutoscaler.
+	WorkloadPolicy *WorkloadPolicy `json:"workloadPolicy,omitempty"`
+}
+
+// Exports defines the structure for the output parameters produced by the virtual-garden component.
+type Exports struct {
+}
+
+// ImageRefs contains the container image references used by the virtual garden components.
+type ImageRefs struct {
+	// KubeAPIServerImage is the image reference for the kube-apiserver.
+	KubeAPIServerImage string `json:"kubeApiServerImage,omitempty"`
+	// KubeControllerManagerImage is the image reference for the kube-controller-manager.
+	KubeControllerManagerImage string `json:"kubeControllerManagerImage,omitempty"`
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+const (
+	minSNITTL = 60
+	maxSNITTL = 600
+)
+
+// regionRegexps holds the per-provider format of a valid ETCDBackup.Region.
+var regionRegexps = map[api.InfrastructureProvider]*regexp.Regexp{
+	api.InfrastructureProviderAWS:   regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]$`),
+	api.InfrastructureProviderGCP:   regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]$`),
+	api.InfrastructureProviderAzure: regexp.MustCompile(`^[a-z]+[0-9]?$`),
+}
+
+// requiredCredentialsKeys holds, per known infrastructure provider, the set of keys that must be present
+// (with a non-empty value) in Credentials.Data.
+var requiredCredentialsKeys = map[api.InfrastructureProvider][]string{
+	api.InfrastructureProviderGCP:      {"serviceaccount.json"},
+	api.InfrastructureProviderAWS:      {"accessKeyID", "secretAccessKey"},
+	api.InfrastructureProviderAzure:    {"clientID", "clientSecret", "tenantID", "subscriptionID"},
+	api.InfrastructureProviderAlicloud: {"accessKeyID", "accessKeySecret"},
+}
+
+// supportedInfrastructureProviders is the set of infrastructure providers accepted for the hosting cluster and
+// the ETCD backup bucket.
+var supportedInfrastructureProviders = map[api.InfrastructureProvider]bool{
+	api.InfrastructureProviderAWS:      true,
+	api.InfrastructureProviderGCP:      true,
+	api.InfrastructureProviderAzure:    true,
+	api.InfrastructureProviderAlicloud: true,
+}
+
+// ValidateImports validates the given Imports configuration.
+func ValidateImports(obj *api.Imports) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateHostingCluster(obj.HostingCluster, field.NewPath("hostingCluster"))...)
+	allErrs = append(allErrs, validateVirtualGarden(obj.VirtualGarden, obj.Credentials, field.NewPath("virtualGarden"))...)
+	allErrs = append(allErrs, validateCredentials(obj.Credentials, field.NewPath("credentials"))...)
+
+	return allErrs
+}
+
+func validateHostingCluster(hostingCluster api.HostingCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(hostingCluster.Kubeconfig) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kubeconfig"), "must provide a kubeconfig"))
+	}
+	if len(hostingCluster.Namespace) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), "must provide a namespace"))
+	}
+	allErrs = append(allErrs, validateInfrastructureProvider(hostingCluster.InfrastructureProvider, fldPath.Child("infrastructureProvider"))...)
+
+	return allErrs
+}
+
+func validateInfrastructureProvider(provider api.InfrastructureProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !supportedInfrastructureProviders[provider] {
+		allErrs = append(allErrs, field.NotSupported(fldPath, provider, supportedInfrastructureProviderValues()))
+	}
+
+	return allErrs
+}
+
+func supportedInfrastructureProviderValues() []string {
+	values := make([]string, 0, len(supportedInfrastructureProviders))
+	for provider := range supportedInfrastructureProviders {
+		values = append(values, string(provider))
+	}
+	return values
+}
+
+func validateVirtualGarden(virtualGarden api.VirtualGarden, credentials map[string]api.Credentials, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if virtualGarden.ETCD != nil {
+		allErrs = append(allErrs, validateETCD(*virtualGarden.ETCD, credentials, fldPath.Child("etcd"))...)
+	}
+
+	if virtualGarden.KubeAPIServer != nil {
+		allErrs = append(allErrs, validateKubeAPIServerExposure(virtualGarden.KubeAPIServer.Exposure, fldPath.Child("exposure"))...)
+		allErrs = append(allErrs, validateKubeAPIServerSNI(virtualGarden.KubeAPIServer.SNI, fldPath.Child("sni"))...)
+	}
+
+	return allErrs
+}
+
+func validateETCD(etcd api.ETCD, credentials map[string]api.Credentials, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if etcd.StorageClassName != nil && len(*etcd.StorageClassName) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("storageClassName"), "must not be empty if provided"))
+	}
+
+	if etcd.Backup != nil {
+		allErrs = append(allErrs, validateETCDBackup(*etcd.Backup, credentials, fldPath.Child("backup"))...)
+	}
+
+	return allErrs
+}
+
+func validateETCDBackup(backup api.ETCDBackup, credentials map[string]api.Credentials, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateInfrastructureProvider(backup.InfrastructureProvider, fldPath.Child("infrastructureProvider"))...)
+
+	if len(backup.Region) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("region"), "must provide a region"))
+	} else if regex, ok := regionRegexps[backup.InfrastructureProvider]; ok && !regex.MatchString(backup.Region) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("region"), backup.Region,
+			fmt.Sprintf("must match the format %q used by %s", regex.String(), backup.InfrastructureProvider)))
+	}
+
+	if backup.InfrastructureProvider == api.InfrastructureProviderAzure && len(backup.ResourceGroup) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resourceGroup"), "must provide a resource group"))
+	}
+
+	if !backup.ManageBucket {
+		switch backup.InfrastructureProvider {
+		case api.InfrastructureProviderAzure:
+			if len(backup.StorageAccount) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("storageAccount"), "must provide a storage account"))
+			} else if !isValidAzureContainerName(backup.StorageAccount) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("storageAccount"), backup.StorageAccount, azureContainerNameRuleMsg))
+			}
+			if len(backup.ContainerName) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("containerName"), "must provide a container name"))
+			} else if !isValidAzureContainerName(backup.ContainerName) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("containerName"), backup.ContainerName, azureContainerNameRuleMsg))
+			}
+		case api.InfrastructureProviderAlicloud:
+			if len(backup.BucketName) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("bucketName"), "must provide a bucket name"))
+			}
+			if len(backup.OSSEndpoint) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("ossEndpoint"), "must provide an OSS endpoint"))
+			}
+		case api.InfrastructureProviderAWS:
+			if len(backup.BucketName) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("bucketName"), "must provide a bucket name"))
+			} else if !isValidS3BucketName(backup.BucketName) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("bucketName"), backup.BucketName, s3BucketNameRuleMsg))
+			}
+		case api.InfrastructureProviderGCP:
+			if len(backup.BucketName) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("bucketName"), "must provide a bucket name"))
+			} else if !isValidGCSBucketName(backup.BucketName) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("bucketName"), backup.BucketName, gcsBucketNameRuleMsg))
+			}
+		default:
+			if len(backup.BucketName) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("bucketName"), "must provide a bucket name"))
+			}
+		}
+	}
+
+	if len(backup.CredentialsRef) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("credentialsRef"), "must provide a reference to credentials"))
+	} else {
+		creds, ok := credentials[backup.CredentialsRef]
+		if !ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("credentialsRef"), backup.CredentialsRef, "does not reference existing credentials"))
+		} else if creds.Type != backup.InfrastructureProvider {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("credentialsRef"), backup.CredentialsRef,
+				fmt.Sprintf("referenced credentials are of type %q but infrastructure provider is %q", creds.Type, backup.InfrastructureProvider)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateKubeAPIServerExposure(exposure *api.KubeAPIServerExposure, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if exposure == nil || exposure.SNI == nil {
+		return allErrs
+	}
+
+	sniPath := fldPath.Child("sni")
+
+	if len(exposure.SNI.Hostnames) == 0 {
+		allErrs = append(allErrs, field.Required(sniPath.Child("hostnames"), "must provide at least one hostname"))
+	}
+	for i, hostname := range exposure.SNI.Hostnames {
+		if !isValidWildcardHostname(hostname) {
+			allErrs = append(allErrs, field.Invalid(sniPath.Child("hostnames").Index(i), hostname,
+				"must be a valid DNS-1123 subdomain, optionally with a leading wildcard label (e.g. \"*.example.com\")"))
+		}
+	}
+
+	if exposure.SNI.TTL != nil && (*exposure.SNI.TTL < minSNITTL || *exposure.SNI.TTL > maxSNITTL) {
+		allErrs = append(allErrs, field.Invalid(sniPath.Child("ttl"), *exposure.SNI.TTL,
+			fmt.Sprintf("must be between %d and %d", minSNITTL, maxSNITTL)))
+	}
+
+	return allErrs
+}
+
+func validateKubeAPIServerSNI(entries []api.SNIEntry, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, entry := range entries {
+		entryPath := fldPath.Index(i)
+
+		if len(entry.SecretName) == 0 {
+			allErrs = append(allErrs, field.Required(entryPath.Child("secretName"), "must provide a secretName"))
+		}
+
+		if len(entry.Hostnames) == 0 {
+			allErrs = append(allErrs, field.Required(entryPath.Child("hostnames"), "must provide at least one hostname"))
+		}
+		for j, hostname := range entry.Hostnames {
+			if !isValidWildcardHostname(hostname) {
+				allErrs = append(allErrs, field.Invalid(entryPath.Child("hostnames").Index(j), hostname,
+					"must be a valid DNS-1123 subdomain, optionally with a leading wildcard label (e.g. \"*.example.com\")"))
+			}
+		}
+
+		if entry.CertManager != nil && len(entry.CertManager.IssuerRef.Name) == 0 {
+			allErrs = append(allErrs, field.Required(entryPath.Child("certManager", "issuerRef", "name"), "must provide an issuerRef name"))
+		}
+	}
+
+	return allErrs
+}
+
+// isValidWildcardHostname reports whether hostname is a valid DNS-1123 subdomain, optionally prefixed with a
+// single "*." wildcard label. A bare "*" is rejected.
+func isValidWildcardHostname(hostname string) bool {
+	if hostname == "*" {
+		return false
+	}
+
+	subdomain := hostname
+	if strings.HasPrefix(hostname, "*.") {
+		subdomain = strings.TrimPrefix(hostname, "*.")
+	}
+
+	return len(utilvalidation.IsDNS1123Subdomain(subdomain)) == 0
+}
+
+const (
+	s3BucketNameRuleMsg       = "must be 3-63 characters, consisting of lowercase letters, digits, dots and dashes"
+	gcsBucketNameRuleMsg      = "must be 3-63 characters, consisting of lowercase letters, digits, dots and dashes (no underscores)"
+	azureContainerNameRuleMsg = "must be 3-63 characters, consisting of lowercase letters, digits and dashes"
+)
+
+var (
+	s3BucketNameRegexp       = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	gcsBucketNameRegexp      = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	azureContainerNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+)
+
+func isValidS3BucketName(name string) bool {
+	return len(name) >= 3 && len(name) <= 63 && s3BucketNameRegexp.MatchString(name)
+}
+
+func isValidGCSBucketName(name string) bool {
+	return len(name) >= 3 && len(name) <= 63 && !strings.Contains(name, "_") && gcsBucketNameRegexp.MatchString(name)
+}
+
+func isValidAzureContainerName(name string) bool {
+	return len(name) >= 3 && len(name) <= 63 && azureContainerNameRegexp.MatchString(name)
+}
+
+func validateCredentials(credentials map[string]api.Credentials, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for name, creds := range credentials {
+		credPath := fldPath.Child(name)
+
+		if len(creds.Type) == 0 {
+			allErrs = append(allErrs, field.Required(credPath.Child("type"), "must provide a type"))
+		}
+		if len(creds.Data) == 0 {
+			allErrs = append(allErrs, field.Required(credPath.Child("data"), "must provide data"))
+		}
+
+		for _, key := range requiredCredentialsKeys[creds.Type] {
+			if len(strings.TrimSpace(creds.Data[key])) == 0 {
+				allErrs = append(allErrs, field.Required(credPath.Child("data").Key(key),
+					fmt.Sprintf("must provide a non-empty %q entry for credentials of type %q", key, creds.Type)))
+			}
+		}
+	}
+
+	return allErrs
+}
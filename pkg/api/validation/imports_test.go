@@ -43,7 +43,7 @@ var _ = Describe("Imports", func() {
 					ETCD: &api.ETCD{
 						Backup: &api.ETCDBackup{
 							InfrastructureProvider: api.InfrastructureProviderGCP,
-							Region:                 "foo",
+							Region:                 "eu-west-1",
 							BucketName:             "bar",
 							CredentialsRef:         "baz",
 						},
@@ -52,7 +52,7 @@ var _ = Describe("Imports", func() {
 				Credentials: map[string]api.Credentials{
 					"baz": {
 						Type: api.InfrastructureProviderGCP,
-						Data: map[string]string{"foo": "bar"},
+						Data: map[string]string{"serviceaccount.json": "bar"},
 					},
 				},
 			}
@@ -144,6 +144,31 @@ var _ = Describe("Imports", func() {
 						})),
 					))
 				})
+
+				It("should pass when manageBucket is true and no bucketName is given", func() {
+					obj.VirtualGarden.ETCD.Backup.ManageBucket = true
+					obj.VirtualGarden.ETCD.Backup.BucketName = ""
+
+					Expect(ValidateImports(obj)).To(BeEmpty())
+				})
+
+				It("should still require region and credentialsRef when manageBucket is true", func() {
+					obj.VirtualGarden.ETCD.Backup.ManageBucket = true
+					obj.VirtualGarden.ETCD.Backup.BucketName = ""
+					obj.VirtualGarden.ETCD.Backup.Region = ""
+					obj.VirtualGarden.ETCD.Backup.CredentialsRef = ""
+
+					Expect(ValidateImports(obj)).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(field.ErrorTypeRequired),
+							"Field": Equal("virtualGarden.etcd.backup.region"),
+						})),
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(field.ErrorTypeRequired),
+							"Field": Equal("virtualGarden.etcd.backup.credentialsRef"),
+						})),
+					))
+				})
 			})
 
 			Context("KubeAPIServer", func() {
@@ -184,10 +209,130 @@ var _ = Describe("Imports", func() {
 						Entry("no hostnames, ttl to low", &api.SNI{TTL: pointer.Int32Ptr(42)}),
 						Entry("no hostnames, ttl to high", &api.SNI{TTL: pointer.Int32Ptr(1000)}),
 					)
+
+					DescribeTable("should fail for invalid hostnames",
+						func(hostname string) {
+							obj.VirtualGarden.KubeAPIServer = &api.KubeAPIServer{
+								Exposure: &api.KubeAPIServerExposure{
+									SNI: &api.SNI{Hostnames: []string{hostname}},
+								},
+							}
+							Expect(ValidateImports(obj)).To(ConsistOf(
+								PointTo(MatchFields(IgnoreExtras, Fields{
+									"Type":  Equal(field.ErrorTypeInvalid),
+									"Field": Equal("virtualGarden.exposure.sni.hostnames[0]"),
+								})),
+							))
+						},
+
+						Entry("empty string", ""),
+						Entry("uppercase", "Foo.Example.Com"),
+						Entry("bare wildcard", "*"),
+					)
 				})
 			})
 		})
 
+		Context("deepened field validation", func() {
+			DescribeTable("should fail for a region not matching the provider's format",
+				func(provider api.InfrastructureProvider, setup func(*api.ETCDBackup), credsData map[string]string) {
+					obj.VirtualGarden.ETCD.Backup.InfrastructureProvider = provider
+					setup(obj.VirtualGarden.ETCD.Backup)
+					obj.Credentials["baz"] = api.Credentials{Type: provider, Data: credsData}
+
+					Expect(ValidateImports(obj)).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(field.ErrorTypeInvalid),
+							"Field": Equal("virtualGarden.etcd.backup.region"),
+						})),
+					))
+				},
+
+				Entry("gcp", api.InfrastructureProviderGCP, func(b *api.ETCDBackup) {
+					b.Region = "not_a_region"
+					b.BucketName = "bar"
+				}, map[string]string{"serviceaccount.json": "bar"}),
+				Entry("aws", api.InfrastructureProviderAWS, func(b *api.ETCDBackup) {
+					b.Region = "not_a_region"
+					b.BucketName = "valid-bucket"
+				}, map[string]string{"accessKeyID": "id", "secretAccessKey": "secret"}),
+				Entry("azure", api.InfrastructureProviderAzure, func(b *api.ETCDBackup) {
+					b.Region = "not_a_region"
+					b.StorageAccount = "validaccount"
+					b.ContainerName = "validcontainer"
+					b.ResourceGroup = "valid-rg"
+				}, map[string]string{"clientID": "a", "clientSecret": "b", "tenantID": "c", "subscriptionID": "d"}),
+			)
+
+			DescribeTable("should fail for a bucket or container name violating the provider's naming rules",
+				func(provider api.InfrastructureProvider, region string, setup func(*api.ETCDBackup), credsData map[string]string, expectedField string) {
+					obj.VirtualGarden.ETCD.Backup.InfrastructureProvider = provider
+					obj.VirtualGarden.ETCD.Backup.Region = region
+					setup(obj.VirtualGarden.ETCD.Backup)
+					obj.Credentials["baz"] = api.Credentials{Type: provider, Data: credsData}
+
+					Expect(ValidateImports(obj)).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(field.ErrorTypeInvalid),
+							"Field": Equal(expectedField),
+						})),
+					))
+				},
+
+				Entry("gcp bucket name", api.InfrastructureProviderGCP, "eu-west-1",
+					func(b *api.ETCDBackup) { b.BucketName = "Invalid_Bucket" },
+					map[string]string{"serviceaccount.json": "bar"}, "virtualGarden.etcd.backup.bucketName"),
+				Entry("aws bucket name", api.InfrastructureProviderAWS, "eu-west-1",
+					func(b *api.ETCDBackup) { b.BucketName = "Invalid_Bucket" },
+					map[string]string{"accessKeyID": "id", "secretAccessKey": "secret"}, "virtualGarden.etcd.backup.bucketName"),
+				Entry("azure storage account name", api.InfrastructureProviderAzure, "westeurope",
+					func(b *api.ETCDBackup) {
+						b.StorageAccount = "Invalid_Account"
+						b.ContainerName = "validcontainer"
+						b.ResourceGroup = "valid-rg"
+					},
+					map[string]string{"clientID": "a", "clientSecret": "b", "tenantID": "c", "subscriptionID": "d"}, "virtualGarden.etcd.backup.storageAccount"),
+			)
+
+			DescribeTable("should fail when required provider-specific credentials keys are missing",
+				func(provider api.InfrastructureProvider, setup func(*api.ETCDBackup), missingKeyFields ...string) {
+					obj.VirtualGarden.ETCD.Backup.InfrastructureProvider = provider
+					setup(obj.VirtualGarden.ETCD.Backup)
+					obj.Credentials["baz"] = api.Credentials{Type: provider, Data: map[string]string{"unrelated": "value"}}
+
+					var expected []interface{}
+					for _, f := range missingKeyFields {
+						expected = append(expected, PointTo(MatchFields(IgnoreExtras, Fields{
+							"Type":  Equal(field.ErrorTypeRequired),
+							"Field": Equal(f),
+						})))
+					}
+
+					Expect(ValidateImports(obj)).To(ConsistOf(expected...))
+				},
+
+				Entry("gcp", api.InfrastructureProviderGCP, func(b *api.ETCDBackup) {
+					b.Region = "eu-west-1"
+					b.BucketName = "bar"
+				}, "credentials.baz.data[serviceaccount.json]"),
+				Entry("aws", api.InfrastructureProviderAWS, func(b *api.ETCDBackup) {
+					b.Region = "eu-west-1"
+					b.BucketName = "bar"
+				}, "credentials.baz.data[accessKeyID]", "credentials.baz.data[secretAccessKey]"),
+				Entry("azure", api.InfrastructureProviderAzure, func(b *api.ETCDBackup) {
+					b.Region = "westeurope"
+					b.StorageAccount = "validaccount"
+					b.ContainerName = "validcontainer"
+					b.ResourceGroup = "valid-rg"
+				}, "credentials.baz.data[clientID]", "credentials.baz.data[clientSecret]", "credentials.baz.data[tenantID]", "credentials.baz.data[subscriptionID]"),
+				Entry("alicloud", api.InfrastructureProviderAlicloud, func(b *api.ETCDBackup) {
+					b.Region = "eu-west-1"
+					b.BucketName = "bar"
+					b.OSSEndpoint = "oss-cn-hangzhou.aliyuncs.com"
+				}, "credentials.baz.data[accessKeyID]", "credentials.baz.data[accessKeySecret]"),
+			)
+		})
+
 		Context("credentials", func() {
 			It("should fail for an invalid configuration", func() {
 				obj.Credentials["foo"] = api.Credentials{}
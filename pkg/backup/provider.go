@@ -0,0 +1,48 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+	"github.com/gardener/virtual-garden/pkg/backup/alicloud"
+	"github.com/gardener/virtual-garden/pkg/backup/aws"
+	"github.com/gardener/virtual-garden/pkg/backup/azure"
+	"github.com/gardener/virtual-garden/pkg/backup/gcp"
+)
+
+// Provider computes the etcd-backup-restore configuration for storing etcd snapshots at an infrastructure
+// provider's object store.
+type Provider interface {
+	// StorageProvider returns the value of the `--storage-provider` flag passed to etcd-backup-restore.
+	StorageProvider() string
+}
+
+// NewBackupProvider creates a new Provider implementation for the given infrastructure provider type.
+func NewBackupProvider(infrastructureProvider api.InfrastructureProvider) (Provider, error) {
+	switch infrastructureProvider {
+	case api.InfrastructureProviderAWS:
+		return aws.NewBackupProvider(), nil
+	case api.InfrastructureProviderGCP:
+		return gcp.NewBackupProvider(), nil
+	case api.InfrastructureProviderAzure:
+		return azure.NewBackupProvider(), nil
+	case api.InfrastructureProviderAlicloud:
+		return alicloud.NewBackupProvider(), nil
+	default:
+		return nil, fmt.Errorf("unsupported infrastructure provider %q", infrastructureProvider)
+	}
+}
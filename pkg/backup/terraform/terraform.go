@@ -0,0 +1,243 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package terraform provisions the object store buckets used for etcd backups via per-provider Terraform
+// modules, using the Terraformer pattern established by Gardener's provider extensions
+// (github.com/gardener/gardener/extensions/pkg/terraformer): the module and variables are rendered into a
+// ConfigMap/Secret pair on the hosting cluster, and a Terraformer pod runs init -> plan -> apply (or destroy).
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener/extensions/pkg/terraformer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+const (
+	// name is the Terraformer identifier used for the config map/secret/pod name prefix.
+	name = "virtual-garden-etcd-backup"
+
+	stateKeyBucketName = "bucket_name"
+)
+
+// Reconcile renders and applies the Terraform module for the given ETCD backup configuration, ensuring the
+// backup bucket exists, and returns its name. It is idempotent: re-running it against an unchanged
+// configuration reuses the persisted tfstate and performs a no-op apply.
+func Reconcile(ctx context.Context, c client.Client, namespace string, backup api.ETCDBackup, creds api.Credentials) (string, error) {
+	config, err := renderConfig(backup, creds)
+	if err != nil {
+		return "", err
+	}
+
+	tf, err := terraformer.NewForConfig(nil, nil, name, namespace, name, config.image)
+	if err != nil {
+		return "", fmt.Errorf("could not create terraformer: %w", err)
+	}
+
+	if err := tf.
+		InitializeWith(ctx, terraformer.DefaultInitializer(c, config.main, config.variables, config.tfVars, terraformer.StateConfigMapInitializerFunc(terraformer.CreateState))).
+		Apply(ctx); err != nil {
+		return "", fmt.Errorf("could not apply terraform config: %w", err)
+	}
+
+	state, err := tf.GetStateOutputVariables(ctx, stateKeyBucketName)
+	if err != nil {
+		return "", fmt.Errorf("could not read terraform state output: %w", err)
+	}
+
+	return state[stateKeyBucketName], nil
+}
+
+// Delete destroys the Terraform-managed bucket for the given ETCD backup configuration and cleans up the
+// ConfigMap/Secret pair together with the persisted tfstate.
+func Delete(ctx context.Context, c client.Client, namespace string, backup api.ETCDBackup, creds api.Credentials) error {
+	config, err := renderConfig(backup, creds)
+	if err != nil {
+		return err
+	}
+
+	tf, err := terraformer.NewForConfig(nil, nil, name, namespace, name, config.image)
+	if err != nil {
+		return fmt.Errorf("could not create terraformer: %w", err)
+	}
+
+	return tf.
+		InitializeWith(ctx, terraformer.DefaultInitializer(c, config.main, config.variables, config.tfVars, terraformer.StateConfigMapInitializerFunc(terraformer.CreateState))).
+		Destroy(ctx)
+}
+
+type renderedConfig struct {
+	image     string
+	main      string
+	variables string
+	tfVars    []byte
+}
+
+// renderConfig produces the per-provider Terraform module (main.tf, variables.tf and terraform.tfvars) for
+// provisioning the etcd backup bucket.
+func renderConfig(backup api.ETCDBackup, creds api.Credentials) (*renderedConfig, error) {
+	switch backup.InfrastructureProvider {
+	case api.InfrastructureProviderAWS:
+		return renderAWSConfig(backup, creds)
+	case api.InfrastructureProviderGCP:
+		return renderGCPConfig(backup, creds)
+	case api.InfrastructureProviderAzure:
+		return renderAzureConfig(backup, creds)
+	case api.InfrastructureProviderAlicloud:
+		return renderAlicloudConfig(backup, creds)
+	default:
+		return nil, fmt.Errorf("unsupported infrastructure provider %q for managed backup bucket", backup.InfrastructureProvider)
+	}
+}
+
+func renderAWSConfig(backup api.ETCDBackup, creds api.Credentials) (*renderedConfig, error) {
+	return &renderedConfig{
+		image: terraformerImage,
+		main: fmt.Sprintf(`
+provider "aws" {
+  access_key = var.ACCESS_KEY_ID
+  secret_key = var.SECRET_ACCESS_KEY
+  region     = %q
+}
+
+resource "aws_s3_bucket" "bucket" {
+  bucket = var.BUCKET_NAME
+  acl    = "private"
+
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm = "AES256"
+      }
+    }
+  }
+}
+
+output "%s" {
+  value = aws_s3_bucket.bucket.id
+}
+`, backup.Region, stateKeyBucketName),
+		variables: `variable "ACCESS_KEY_ID" { type = string }
+variable "SECRET_ACCESS_KEY" { type = string }
+variable "BUCKET_NAME" { type = string }
+`,
+		tfVars: []byte(fmt.Sprintf("ACCESS_KEY_ID = %q\nSECRET_ACCESS_KEY = %q\nBUCKET_NAME = %q\n",
+			creds.Data["accessKeyID"], creds.Data["secretAccessKey"], backup.BucketName)),
+	}, nil
+}
+
+func renderGCPConfig(backup api.ETCDBackup, creds api.Credentials) (*renderedConfig, error) {
+	return &renderedConfig{
+		image: terraformerImage,
+		main: fmt.Sprintf(`
+provider "google" {
+  credentials = var.SERVICEACCOUNT_JSON
+  region      = %q
+}
+
+resource "google_storage_bucket" "bucket" {
+  name     = var.BUCKET_NAME
+  location = %q
+}
+
+output "%s" {
+  value = google_storage_bucket.bucket.name
+}
+`, backup.Region, backup.Region, stateKeyBucketName),
+		variables: `variable "SERVICEACCOUNT_JSON" { type = string }
+variable "BUCKET_NAME" { type = string }
+`,
+		tfVars: []byte(fmt.Sprintf("SERVICEACCOUNT_JSON = %q\nBUCKET_NAME = %q\n",
+			creds.Data["serviceaccount.json"], backup.BucketName)),
+	}, nil
+}
+
+func renderAzureConfig(backup api.ETCDBackup, creds api.Credentials) (*renderedConfig, error) {
+	return &renderedConfig{
+		image: terraformerImage,
+		main: fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+
+  client_id       = var.CLIENT_ID
+  client_secret   = var.CLIENT_SECRET
+  tenant_id       = var.TENANT_ID
+  subscription_id = var.SUBSCRIPTION_ID
+}
+
+resource "azurerm_storage_account" "account" {
+  name                     = var.STORAGE_ACCOUNT
+  resource_group_name      = var.RESOURCE_GROUP
+  location                 = %q
+  account_tier             = "Standard"
+  account_replication_type = "LRS"
+}
+
+resource "azurerm_storage_container" "container" {
+  name                  = var.CONTAINER_NAME
+  storage_account_name  = azurerm_storage_account.account.name
+  container_access_type = "private"
+}
+
+output "%s" {
+  value = azurerm_storage_container.container.name
+}
+`, backup.Region, stateKeyBucketName),
+		variables: `variable "CLIENT_ID" { type = string }
+variable "CLIENT_SECRET" { type = string }
+variable "TENANT_ID" { type = string }
+variable "SUBSCRIPTION_ID" { type = string }
+variable "RESOURCE_GROUP" { type = string }
+variable "STORAGE_ACCOUNT" { type = string }
+variable "CONTAINER_NAME" { type = string }
+`,
+		tfVars: []byte(fmt.Sprintf("CLIENT_ID = %q\nCLIENT_SECRET = %q\nTENANT_ID = %q\nSUBSCRIPTION_ID = %q\nRESOURCE_GROUP = %q\nSTORAGE_ACCOUNT = %q\nCONTAINER_NAME = %q\n",
+			creds.Data["clientID"], creds.Data["clientSecret"], creds.Data["tenantID"], creds.Data["subscriptionID"], backup.ResourceGroup, backup.StorageAccount, backup.ContainerName)),
+	}, nil
+}
+
+func renderAlicloudConfig(backup api.ETCDBackup, creds api.Credentials) (*renderedConfig, error) {
+	return &renderedConfig{
+		image: terraformerImage,
+		main: fmt.Sprintf(`
+provider "alicloud" {
+  access_key = var.ACCESS_KEY_ID
+  secret_key = var.ACCESS_KEY_SECRET
+  region     = %q
+}
+
+resource "alicloud_oss_bucket" "bucket" {
+  bucket = var.BUCKET_NAME
+  acl    = "private"
+}
+
+output "%s" {
+  value = alicloud_oss_bucket.bucket.bucket
+}
+`, backup.Region, stateKeyBucketName),
+		variables: `variable "ACCESS_KEY_ID" { type = string }
+variable "ACCESS_KEY_SECRET" { type = string }
+variable "BUCKET_NAME" { type = string }
+`,
+		tfVars: []byte(fmt.Sprintf("ACCESS_KEY_ID = %q\nACCESS_KEY_SECRET = %q\nBUCKET_NAME = %q\n",
+			creds.Data["accessKeyID"], creds.Data["accessKeySecret"], backup.BucketName)),
+	}, nil
+}
+
+// terraformerImage is the image running the per-provider terraform CLI plus provider plugins.
+const terraformerImage = "eu.gcr.io/gardener-project/gardener/terraformer:v2.13.0"
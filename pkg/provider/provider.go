@@ -0,0 +1,46 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+	"github.com/gardener/virtual-garden/pkg/provider/alicloud"
+	"github.com/gardener/virtual-garden/pkg/provider/aws"
+	"github.com/gardener/virtual-garden/pkg/provider/azure"
+	"github.com/gardener/virtual-garden/pkg/provider/gcp"
+)
+
+// InfrastructureProvider computes the storage class configuration for the infrastructure the hosting cluster runs on.
+type InfrastructureProvider interface {
+	ComputeStorageClassConfiguration() (provisioner string, parameters map[string]string)
+}
+
+// NewInfrastructureProvider creates a new InfrastructureProvider implementation for the given infrastructure provider type.
+func NewInfrastructureProvider(infrastructureProvider api.InfrastructureProvider) (InfrastructureProvider, error) {
+	switch infrastructureProvider {
+	case api.InfrastructureProviderAWS:
+		return aws.NewInfrastructureProvider(), nil
+	case api.InfrastructureProviderGCP:
+		return gcp.NewInfrastructureProvider(), nil
+	case api.InfrastructureProviderAzure:
+		return azure.NewInfrastructureProvider(), nil
+	case api.InfrastructureProviderAlicloud:
+		return alicloud.NewInfrastructureProvider(), nil
+	default:
+		return nil, fmt.Errorf("unsupported infrastructure provider %q", infrastructureProvider)
+	}
+}
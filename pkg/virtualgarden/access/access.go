@@ -0,0 +1,226 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package access provisions virtual-garden access credentials for runtime-cluster workloads: a ServiceAccount and
+// ClusterRoleBinding(s) in the virtual garden, a token minted via the TokenRequest API, and a kubeconfig Secret in
+// the runtime cluster for the consuming component's Deployment to mount.
+package access
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/virtual-garden/pkg/util"
+)
+
+const (
+	// AnnotationTokenExpiresAt records when the current token expires, so Reconcile knows when to rotate it.
+	AnnotationTokenExpiresAt = "access.virtual-garden.gardener.cloud/token-expires-at"
+
+	// DefaultTokenValidity is the token lifetime used when AccessRequest.TokenValidity is zero.
+	DefaultTokenValidity = 24 * time.Hour
+
+	secretKeyKubeconfig = "kubeconfig"
+	secretKeyToken      = "token"
+)
+
+// AccessRequest describes a named consumer's access to the virtual garden.
+type AccessRequest struct {
+	// Name is shared by the ServiceAccount and ClusterRoleBinding(s) in the virtual garden and the kubeconfig
+	// Secret in the runtime cluster.
+	Name string
+	// Namespace is the namespace of the ServiceAccount in the virtual garden and of the kubeconfig Secret in the
+	// runtime cluster.
+	Namespace string
+	// ClusterRoles are the cluster roles the ServiceAccount is bound to, one ClusterRoleBinding per entry.
+	ClusterRoles []string
+	// ServerURL is the virtual garden kube-apiserver URL embedded in the generated kubeconfig.
+	ServerURL string
+	// CABundle is the virtual garden kube-apiserver CA embedded in the generated kubeconfig.
+	CABundle []byte
+	// TokenValidity is the requested token lifetime. Defaults to DefaultTokenValidity if zero. The token is
+	// rotated once it has expired.
+	TokenValidity time.Duration
+}
+
+// Reconcile idempotently provisions req's ServiceAccount and ClusterRoleBinding(s) in virtualClient and a
+// kubeconfig Secret in runtimeClient, rotating the underlying token once it has expired. It returns the name of
+// the kubeconfig Secret so component deployers can mount it.
+func Reconcile(ctx context.Context, runtimeClient, virtualClient client.Client, req AccessRequest) (string, error) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, virtualClient, sa, func() error { return nil }); err != nil {
+		return "", fmt.Errorf("could not reconcile service account %q: %w", req.Name, err)
+	}
+
+	for _, role := range req.ClusterRoles {
+		crb := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(req.Name, role)}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, virtualClient, crb, func() error {
+			crb.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     role,
+			}
+			crb.Subjects = []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      req.Name,
+					Namespace: req.Namespace,
+				},
+			}
+			return nil
+		}); err != nil {
+			return "", fmt.Errorf("could not reconcile cluster role binding for role %q: %w", role, err)
+		}
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	if err := runtimeClient.Get(ctx, util.GetKey(secret), secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("could not get kubeconfig secret %q: %w", req.Name, err)
+		}
+	}
+
+	if !hasValidToken(secret) {
+		if err := rotateToken(ctx, runtimeClient, virtualClient, sa, secret, req); err != nil {
+			return "", err
+		}
+	}
+
+	return req.Name, nil
+}
+
+// Delete removes req's kubeconfig Secret from runtimeClient and its ServiceAccount and ClusterRoleBinding(s) from
+// virtualClient.
+func Delete(ctx context.Context, runtimeClient, virtualClient client.Client, req AccessRequest) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	if err := runtimeClient.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	for _, role := range req.ClusterRoles {
+		crb := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleBindingName(req.Name, role)}}
+		if err := virtualClient.Delete(ctx, crb); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	return client.IgnoreNotFound(virtualClient.Delete(ctx, sa))
+}
+
+func clusterRoleBindingName(name, role string) string {
+	return name + "--" + role
+}
+
+// hasValidToken reports whether secret carries a token that, per its AnnotationTokenExpiresAt annotation, has not
+// expired yet.
+func hasValidToken(secret *corev1.Secret) bool {
+	if len(secret.Data[secretKeyToken]) == 0 {
+		return false
+	}
+
+	raw, ok := secret.Annotations[AnnotationTokenExpiresAt]
+	if !ok {
+		return false
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return false
+	}
+
+	return time.Now().Before(expiresAt)
+}
+
+// rotateToken mints a fresh token via the TokenRequest API and writes it, alongside a matching kubeconfig, into
+// secret in runtimeClient.
+func rotateToken(ctx context.Context, runtimeClient, virtualClient client.Client, sa *corev1.ServiceAccount, secret *corev1.Secret, req AccessRequest) error {
+	validity := req.TokenValidity
+	if validity == 0 {
+		validity = DefaultTokenValidity
+	}
+	expirationSeconds := int64(validity.Seconds())
+
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	if err := virtualClient.SubResource("token").Create(ctx, sa, tokenRequest); err != nil {
+		return fmt.Errorf("could not create token for service account %q: %w", req.Name, err)
+	}
+
+	kubeconfig := kubeconfigFor(req, tokenRequest.Status.Token)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, runtimeClient, secret, func() error {
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[AnnotationTokenExpiresAt] = tokenRequest.Status.ExpirationTimestamp.Time.Format(time.RFC3339)
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[secretKeyToken] = []byte(tokenRequest.Status.Token)
+		secret.Data[secretKeyKubeconfig] = kubeconfig
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not reconcile kubeconfig secret %q: %w", req.Name, err)
+	}
+
+	return nil
+}
+
+func kubeconfigFor(req AccessRequest, token string) []byte {
+	return []byte(fmt.Sprintf(kubeconfigTemplate,
+		base64.StdEncoding.EncodeToString(req.CABundle),
+		req.ServerURL,
+		req.Name,
+		req.Name,
+		req.Name,
+		req.Name,
+		token,
+	))
+}
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    certificate-authority-data: %s
+    server: %s
+  name: virtual-garden
+contexts:
+- context:
+    cluster: virtual-garden
+    user: %s
+  name: %s
+current-context: %s
+users:
+- name: %s
+  user:
+    token: %s
+`
@@ -0,0 +1,408 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+
+	controllermanagerconfigv1alpha1 "github.com/gardener/gardener/pkg/controllermanager/apis/config/v1alpha1"
+	"github.com/ghodss/yaml"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/gardener/virtual-garden/pkg/util"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/access"
+)
+
+const (
+	GardenerControllerManagerServiceName                  = Prefix + "-gardener-controller-manager"
+	GardenerControllerManagerDeploymentName               = Prefix + "-gardener-controller-manager"
+	GardenerControllerManagerSecretNameAccessKubeconfig   = Prefix + "-gardener-controller-manager-kubeconfig"
+	GardenerControllerManagerConfigMapNameComponentConfig = Prefix + "-gardener-controller-manager-config"
+	GardenerControllerManagerPodDisruptionBudgetName      = Prefix + "-gardener-controller-manager"
+	GardenerControllerManagerVerticalPodAutoscalerName    = Prefix + "-gardener-controller-manager"
+	GardenerControllerManagerClusterRoleName              = Prefix + "-gardener-controller-manager"
+
+	// ChecksumKeyGardenerControllerManagerAccessKubeconfig is the checksums map key for the
+	// gardener-controller-manager's access-kubeconfig secret, so the deployment rolls whenever it is regenerated.
+	ChecksumKeyGardenerControllerManagerAccessKubeconfig = "checksum/secret-" + GardenerControllerManagerSecretNameAccessKubeconfig
+	// ChecksumKeyGardenerControllerManagerComponentConfig is the checksums map key for the
+	// gardener-controller-manager's component-config ConfigMap.
+	ChecksumKeyGardenerControllerManagerComponentConfig = "checksum/configmap-" + GardenerControllerManagerConfigMapNameComponentConfig
+
+	gardenerControllerManagerContainerName             = "gardener-controller-manager"
+	volumeNameGardenerControllerManagerKubeconfig      = "kubeconfig"
+	volumeNameGardenerControllerManagerComponentConfig = "config"
+
+	gardenerControllerManagerMetricsPort = 2718
+	gardenerControllerManagerHTTPSPort   = 2719
+)
+
+func gardenerControllerManagerLabels() map[string]string {
+	return map[string]string{
+		LabelKeyApp:       Prefix,
+		LabelKeyComponent: "gardener-controller-manager",
+	}
+}
+
+// DeployGardenerControllerManager reconciles the full set of runtime resources backing the
+// gardener-controller-manager: its access-kubeconfig Secret, component-config ConfigMap, RBAC, PodDisruptionBudget,
+// Service, VerticalPodAutoscaler and Deployment.
+func (o *operation) DeployGardenerControllerManager(ctx context.Context) error {
+	if o.imports.VirtualGarden.GardenerControllerManager == nil {
+		return nil
+	}
+
+	if err := o.deployGardenerControllerManagerClusterRole(ctx); err != nil {
+		return err
+	}
+
+	if err := o.deployGardenerControllerManagerSecretAccessKubeconfig(ctx); err != nil {
+		return err
+	}
+
+	if err := o.deployGardenerControllerManagerConfigMapComponentConfig(ctx); err != nil {
+		return err
+	}
+
+	if err := o.deployGardenerControllerManagerService(ctx); err != nil {
+		return err
+	}
+
+	checksums, err := o.computeGardenerControllerManagerChecksums(ctx)
+	if err != nil {
+		return err
+	}
+
+	return o.deployGardenerControllerManagerDeployment(ctx, checksums)
+}
+
+// DeleteGardenerControllerManager deletes every runtime resource reconciled by DeployGardenerControllerManager.
+func (o *operation) DeleteGardenerControllerManager(ctx context.Context) error {
+	deployment := o.emptyDeployment(GardenerControllerManagerDeploymentName)
+	if err := o.client.Delete(ctx, deployment); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	if err := o.deleteWorkloadPolicies(ctx, GardenerControllerManagerPodDisruptionBudgetName, GardenerControllerManagerVerticalPodAutoscalerName); err != nil {
+		return err
+	}
+
+	service := o.emptyGardenerControllerManagerService()
+	if err := o.client.Delete(ctx, service); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	configMap := o.emptyGardenerControllerManagerConfigMap()
+	if err := o.client.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	req, err := o.virtualGardenAccessRequest(ctx, GardenerControllerManagerSecretNameAccessKubeconfig, []string{GardenerControllerManagerClusterRoleName})
+	if err != nil {
+		return err
+	}
+	if err := access.Delete(ctx, o.client, o.virtualGardenClient, req); err != nil {
+		return err
+	}
+
+	clusterRole := o.emptyGardenerControllerManagerClusterRole()
+	return client.IgnoreNotFound(o.virtualGardenClient.Delete(ctx, clusterRole))
+}
+
+// deployGardenerControllerManagerSecretAccessKubeconfig provisions gardener-controller-manager's virtual garden
+// access identity and kubeconfig via the access package: a ServiceAccount and ClusterRoleBinding in the virtual
+// garden, backed by a TokenRequest-minted token the virtual garden kube-apiserver can actually validate.
+func (o *operation) deployGardenerControllerManagerSecretAccessKubeconfig(ctx context.Context) error {
+	req, err := o.virtualGardenAccessRequest(ctx, GardenerControllerManagerSecretNameAccessKubeconfig, []string{GardenerControllerManagerClusterRoleName})
+	if err != nil {
+		return err
+	}
+
+	_, err = access.Reconcile(ctx, o.client, o.virtualGardenClient, req)
+	return err
+}
+
+func (o *operation) emptyGardenerControllerManagerConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: GardenerControllerManagerConfigMapNameComponentConfig, Namespace: o.namespace}}
+}
+
+func (o *operation) deployGardenerControllerManagerConfigMapComponentConfig(ctx context.Context) error {
+	gcm := o.imports.VirtualGarden.GardenerControllerManager
+
+	logLevel := gcm.LogLevel
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	config := controllermanagerconfigv1alpha1.ControllerManagerConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: controllermanagerconfigv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ControllerManagerConfiguration",
+		},
+		LogLevel: logLevel,
+		Server: controllermanagerconfigv1alpha1.ServerConfiguration{
+			HTTPS: controllermanagerconfigv1alpha1.HTTPSServer{
+				Port: gardenerControllerManagerHTTPSPort,
+			},
+			Metrics: controllermanagerconfigv1alpha1.MetricsServer{
+				Port: gardenerControllerManagerMetricsPort,
+			},
+		},
+		FeatureGates: gcm.FeatureGates,
+	}
+
+	raw, err := yaml.Marshal(&config)
+	if err != nil {
+		return err
+	}
+
+	configMap := o.emptyGardenerControllerManagerConfigMap()
+
+	_, err = controllerutil.CreateOrUpdate(ctx, o.client, configMap, func() error {
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data["config.yaml"] = string(raw)
+		return nil
+	})
+
+	return err
+}
+
+func (o *operation) emptyGardenerControllerManagerClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: GardenerControllerManagerClusterRoleName}}
+}
+
+// deployGardenerControllerManagerClusterRole reconciles the virtual garden ClusterRole that
+// deployGardenerControllerManagerSecretAccessKubeconfig binds gardener-controller-manager's access identity to.
+func (o *operation) deployGardenerControllerManagerClusterRole(ctx context.Context) error {
+	clusterRole := o.emptyGardenerControllerManagerClusterRole()
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.virtualGardenClient, clusterRole, func() error {
+		clusterRole.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"core.gardener.cloud"},
+				Resources: []string{"*"},
+				Verbs:     []string{"*"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"namespaces", "secrets", "serviceaccounts", "events"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			},
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"rbac.authorization.k8s.io"},
+				Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete", "bind", "escalate"},
+			},
+		}
+		return nil
+	})
+
+	return err
+}
+
+func (o *operation) emptyGardenerControllerManagerService() *corev1.Service {
+	return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: GardenerControllerManagerServiceName, Namespace: o.namespace}}
+}
+
+func (o *operation) deployGardenerControllerManagerService(ctx context.Context) error {
+	service := o.emptyGardenerControllerManagerService()
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.client, service, func() error {
+		service.ObjectMeta.Labels = gardenerControllerManagerLabels()
+		service.Spec = corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  gardenerControllerManagerLabels(),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Port:       gardenerControllerManagerMetricsPort,
+					TargetPort: intstr.FromInt(gardenerControllerManagerMetricsPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       "https",
+					Port:       gardenerControllerManagerHTTPSPort,
+					TargetPort: intstr.FromInt(gardenerControllerManagerHTTPSPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		}
+		return nil
+	})
+
+	return err
+}
+
+func (o *operation) computeGardenerControllerManagerChecksums(ctx context.Context) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	secret := o.emptySecret(GardenerControllerManagerSecretNameAccessKubeconfig)
+	if err := o.client.Get(ctx, util.GetKey(secret), secret); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	} else {
+		checksums[ChecksumKeyGardenerControllerManagerAccessKubeconfig] = utils.ComputeChecksum(secret.Data)
+	}
+
+	configMap := o.emptyGardenerControllerManagerConfigMap()
+	if err := o.client.Get(ctx, util.GetKey(configMap), configMap); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	} else {
+		checksums[ChecksumKeyGardenerControllerManagerComponentConfig] = utils.ComputeChecksum(configMap.Data)
+	}
+
+	return checksums, nil
+}
+
+func (o *operation) deployGardenerControllerManagerDeployment(ctx context.Context, checksums map[string]string) error {
+	o.log.Infof("Deploying deployment %s", GardenerControllerManagerDeploymentName)
+
+	deployment := o.emptyDeployment(GardenerControllerManagerDeploymentName)
+
+	gcm := o.imports.VirtualGarden.GardenerControllerManager
+	replicas := pointer.Int32Ptr(1)
+	if gcm.Replicas > 0 {
+		replicas = pointer.Int32Ptr(int32(gcm.Replicas))
+	}
+
+	annotations := o.addChecksumsToAnnotations(checksums, []string{
+		ChecksumKeyGardenerControllerManagerAccessKubeconfig,
+		ChecksumKeyGardenerControllerManagerComponentConfig,
+		ChecksumKeyKubeAPIServerCA,
+	})
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.client, deployment, func() error {
+		deployment.ObjectMeta.Labels = gardenerControllerManagerLabels()
+
+		deployment.Spec = appsv1.DeploymentSpec{
+			RevisionHistoryLimit: pointer.Int32Ptr(0),
+			Replicas:             replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: gardenerControllerManagerLabels(),
+			},
+
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+					Labels: map[string]string{
+						LabelKeyApp:                            Prefix,
+						LabelKeyComponent:                      "gardener-controller-manager",
+						"networking.gardener.cloud/to-dns":     LabelValueAllowed,
+						"networking.gardener.cloud/to-ingress": LabelValueAllowed,
+						"networking.gardener.cloud/to-world":   LabelValueAllowed,
+					},
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: pointer.BoolPtr(false),
+					PriorityClassName:            o.imports.VirtualGarden.PriorityClassName,
+					Containers: []corev1.Container{
+						{
+							Name:            gardenerControllerManagerContainerName,
+							Image:           gcm.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         o.getGardenerControllerManagerCommand(),
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path:   "/healthz",
+										Port:   intstr.IntOrString{Type: intstr.Int, IntVal: gardenerControllerManagerHTTPSPort},
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								InitialDelaySeconds: 15,
+								TimeoutSeconds:      15,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								FailureThreshold:    2,
+							},
+							TerminationMessagePath:   "/dev/termination-log",
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							Ports: []corev1.ContainerPort{
+								{Name: "metrics", ContainerPort: gardenerControllerManagerMetricsPort, Protocol: corev1.ProtocolTCP},
+								{Name: "https", ContainerPort: gardenerControllerManagerHTTPSPort, Protocol: corev1.ProtocolTCP},
+							},
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("750m"),
+									corev1.ResourceMemory: resource.MustParse("512Mi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("50m"),
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      volumeNameGardenerControllerManagerKubeconfig,
+									MountPath: "/var/lib/gardener-controller-manager",
+								},
+								{
+									Name:      volumeNameGardenerControllerManagerComponentConfig,
+									MountPath: "/etc/gardener-controller-manager/config",
+								},
+							},
+						},
+					},
+					DNSPolicy:                     corev1.DNSClusterFirst,
+					RestartPolicy:                 corev1.RestartPolicyAlways,
+					TerminationGracePeriodSeconds: pointer.Int64Ptr(30),
+					Volumes: []corev1.Volume{
+						volumeWithSecretSource(volumeNameGardenerControllerManagerKubeconfig, GardenerControllerManagerSecretNameAccessKubeconfig),
+						volumeWithConfigMapSource(volumeNameGardenerControllerManagerComponentConfig, GardenerControllerManagerConfigMapNameComponentConfig),
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.ensureWorkloadPolicies(ctx, deployment, workloadPolicyOptions(
+		GardenerControllerManagerPodDisruptionBudgetName,
+		GardenerControllerManagerVerticalPodAutoscalerName,
+		gardenerControllerManagerLabels(),
+		gcm.WorkloadPolicy,
+	))
+}
+
+// getGardenerControllerManagerCommand renders the gardener-controller-manager command line.
+func (o *operation) getGardenerControllerManagerCommand() []string {
+	return []string{
+		"/gardener-controller-manager",
+		"--config=/etc/gardener-controller-manager/config/config.yaml",
+	}
+}
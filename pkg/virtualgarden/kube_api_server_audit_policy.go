@@ -0,0 +1,89 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+// getAuditPolicy renders imports.VirtualGarden.KubeAPIServer.Audit.Policy into the audit.k8s.io/v1 Policy
+// consumed by the audit-policy ConfigMap mounted at /etc/kube-apiserver/audit. If no policy is configured, a
+// conservative default that logs metadata for all requests is rendered instead.
+func (o *operation) getAuditPolicy() ([]byte, error) {
+	rules := o.getAuditPolicyRules()
+
+	policy := auditv1.Policy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: auditv1.SchemeGroupVersion.String(),
+			Kind:       "Policy",
+		},
+		Rules: rules,
+	}
+
+	return yaml.Marshal(&policy)
+}
+
+func (o *operation) getAuditPolicyRules() []auditv1.PolicyRule {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+	if apiServer.Audit == nil || apiServer.Audit.Policy == nil || len(apiServer.Audit.Policy.Rules) == 0 {
+		return []auditv1.PolicyRule{{Level: auditv1.LevelMetadata}}
+	}
+
+	rules := make([]auditv1.PolicyRule, 0, len(apiServer.Audit.Policy.Rules))
+	for _, rule := range apiServer.Audit.Policy.Rules {
+		rules = append(rules, auditv1.PolicyRule{
+			Level:      auditv1.Level(rule.Level),
+			Resources:  convertAuditGroupResources(rule.Resources),
+			Namespaces: rule.Namespaces,
+			Verbs:      rule.Verbs,
+			OmitStages: convertAuditOmitStages(rule.OmitStages),
+		})
+	}
+
+	return rules
+}
+
+func convertAuditGroupResources(groupResources []api.GroupResources) []auditv1.GroupResources {
+	if len(groupResources) == 0 {
+		return nil
+	}
+
+	converted := make([]auditv1.GroupResources, 0, len(groupResources))
+	for _, gr := range groupResources {
+		converted = append(converted, auditv1.GroupResources{
+			Group:     gr.Group,
+			Resources: gr.Resources,
+		})
+	}
+
+	return converted
+}
+
+func convertAuditOmitStages(stages []string) []auditv1.Stage {
+	if len(stages) == 0 {
+		return nil
+	}
+
+	converted := make([]auditv1.Stage, 0, len(stages))
+	for _, stage := range stages {
+		converted = append(converted, auditv1.Stage(stage))
+	}
+
+	return converted
+}
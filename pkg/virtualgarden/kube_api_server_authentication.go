@@ -0,0 +1,218 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/secretsmanager"
+)
+
+const (
+	KubeApiServerSecretNameOidcAuthenticationWebhookConfig  = Prefix + "-kube-apiserver-oidc-authentication-webhook-config"
+	KubeApiServerSecretNameOidcCABundle                     = Prefix + "-kube-apiserver-oidc-ca-bundle"
+	KubeApiServerSecretNameAuthenticationConfig             = Prefix + "-kube-apiserver-authentication-config"
+	ChecksumKeyKubeAPIServerOidcAuthenticationWebhookConfig = "checksum/secret-" + KubeApiServerSecretNameOidcAuthenticationWebhookConfig
+
+	volumeNameKubeAPIServerOidcAuthenticationWebhookConfig = "authentication-webhook"
+	volumeNameKubeAPIServerOidcCABundle                    = "oidc-ca-bundle"
+	volumeNameKubeAPIServerAuthenticationConfig            = "authentication-config"
+)
+
+// getOIDC returns the OIDC authenticator configuration, preferring Authentication.OIDC over the deprecated
+// top-level OidcIssuerURL, which is translated using the historical hard-coded client ID and claims.
+func (o *operation) getOIDC() *api.OIDCAuthentication {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+
+	if apiServer.Authentication != nil && apiServer.Authentication.OIDC != nil {
+		return apiServer.Authentication.OIDC
+	}
+
+	if apiServer.OidcIssuerURL == nil {
+		return nil
+	}
+
+	return &api.OIDCAuthentication{
+		IssuerURL:     *apiServer.OidcIssuerURL,
+		ClientID:      "kube-kubectl",
+		UsernameClaim: "email",
+		GroupsClaim:   "groups",
+	}
+}
+
+// isStructuredAuthenticationConfigEnabled reports whether the newer AuthenticationConfiguration file format is
+// configured, taking precedence over getOIDC.
+func (o *operation) isStructuredAuthenticationConfigEnabled() bool {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+	return apiServer.Authentication != nil && apiServer.Authentication.StructuredConfig != nil
+}
+
+// isBasicAuthEnabled reports whether the deprecated admin/system:masters basic-auth credential should be
+// created. It is opt-in, and ignored once OIDC or the structured authentication config is configured.
+func (o *operation) isBasicAuthEnabled() bool {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+	if o.isStructuredAuthenticationConfigEnabled() || o.getOIDC() != nil {
+		return false
+	}
+	return apiServer.Authentication != nil && apiServer.Authentication.BasicAuth
+}
+
+// hasOIDCCABundle reports whether a CA bundle is configured for the OIDC authenticator.
+func (o *operation) hasOIDCCABundle() bool {
+	oidc := o.getOIDC()
+	return oidc != nil && len(oidc.CABundle) > 0
+}
+
+// getAuthenticationCommand renders the authentication-related kube-apiserver flags: either
+// --authentication-config (structured config, taking precedence) or the --oidc-* flags.
+func (o *operation) getAuthenticationCommand() []string {
+	var command []string
+
+	switch {
+	case o.isStructuredAuthenticationConfigEnabled():
+		command = append(command, "--authentication-config=/etc/kube-apiserver/authentication-config/config.yaml")
+	case o.getOIDC() != nil:
+		oidc := o.getOIDC()
+		usernameClaim, groupsClaim := oidc.UsernameClaim, oidc.GroupsClaim
+		if usernameClaim == "" {
+			usernameClaim = "email"
+		}
+		if groupsClaim == "" {
+			groupsClaim = "groups"
+		}
+		command = append(command,
+			"--oidc-client-id="+oidc.ClientID,
+			"--oidc-issuer-url="+oidc.IssuerURL,
+			"--oidc-username-claim="+usernameClaim,
+			"--oidc-groups-claim="+groupsClaim,
+		)
+		if o.hasOIDCCABundle() {
+			command = append(command, "--oidc-ca-file=/etc/kube-apiserver/oidc-ca/ca.crt")
+		}
+	}
+
+	return command
+}
+
+func (o *operation) deployKubeApiServerSecretOidcAuthenticationWebhookConfig(ctx context.Context) error {
+	if !o.isOidcWebhookAuthenticatorEnabled() {
+		return nil
+	}
+
+	kubeconfig := o.imports.VirtualGarden.KubeAPIServer.OidcWebhookAuthenticator.Kubeconfig
+
+	if o.isOidcWebhookTokenProjectionEnabled() {
+		rewritten, err := rewriteKubeconfigWithTokenFile(string(kubeconfig), mountPathOidcWebhookToken+"/token")
+		if err != nil {
+			return err
+		}
+		kubeconfig = []byte(rewritten)
+	}
+
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:        KubeApiServerSecretNameOidcAuthenticationWebhookConfig,
+		GracePeriod: o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{"kubeconfig.yaml": kubeconfig}, nil
+		},
+	})
+
+	return err
+}
+
+func (o *operation) deployKubeApiServerSecretOidcCABundle(ctx context.Context) error {
+	if !o.hasOIDCCABundle() {
+		return nil
+	}
+
+	caBundle := o.getOIDC().CABundle
+
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:        KubeApiServerSecretNameOidcCABundle,
+		GracePeriod: o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{"ca.crt": caBundle}, nil
+		},
+	})
+
+	return err
+}
+
+func (o *operation) deployKubeApiServerSecretAuthenticationConfig(ctx context.Context) error {
+	if !o.isStructuredAuthenticationConfigEnabled() {
+		return nil
+	}
+
+	config := o.imports.VirtualGarden.KubeAPIServer.Authentication.StructuredConfig.Config
+
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:        KubeApiServerSecretNameAuthenticationConfig,
+		GracePeriod: o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{"config.yaml": []byte(config)}, nil
+		},
+	})
+
+	return err
+}
+
+// getAuthenticationVolumes returns the volumes for whichever OIDC/structured-authentication secrets are currently
+// configured (on top of the webhook token authenticator's own volume, which getAPIServerVolumes wires directly),
+// mounting the current hashed version of each.
+func (o *operation) getAuthenticationVolumes(ctx context.Context) ([]corev1.Volume, error) {
+	var volumes []corev1.Volume
+
+	if o.hasOIDCCABundle() {
+		name, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameOidcCABundle)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerOidcCABundle, name))
+	}
+
+	if o.isStructuredAuthenticationConfigEnabled() {
+		name, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameAuthenticationConfig)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerAuthenticationConfig, name))
+	}
+
+	return volumes, nil
+}
+
+// getAuthenticationVolumeMounts returns the container volume mounts matching getAuthenticationVolumes.
+func (o *operation) getAuthenticationVolumeMounts() []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+
+	if o.hasOIDCCABundle() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volumeNameKubeAPIServerOidcCABundle,
+			MountPath: "/etc/kube-apiserver/oidc-ca",
+		})
+	}
+
+	if o.isStructuredAuthenticationConfigEnabled() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volumeNameKubeAPIServerAuthenticationConfig,
+			MountPath: "/etc/kube-apiserver/authentication-config",
+		})
+	}
+
+	return volumeMounts
+}
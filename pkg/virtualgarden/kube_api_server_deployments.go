@@ -18,20 +18,46 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"sort"
+	"strings"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv1beta2 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1beta2"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/virtual-garden/pkg/api"
 )
 
 const (
 	KubeAPIServerDeploymentNameAPIServer         = Prefix + "-kube-apiserver"
 	KubeAPIServerDeploymentNameControllerManager = Prefix + "-kube-controller-manager"
+
+	KubeAPIServerPodDisruptionBudgetName   = Prefix + "-kube-apiserver"
+	KubeAPIServerVerticalPodAutoscalerName = Prefix + "-kube-apiserver"
+
+	volumeNameKubeAPIServerAuditLog = "audit-log"
+
+	auditLogSidecarContainerName = "audit-log-shipper"
+
+	volumeNameAuditWebhookToken = "audit-webhook-token"
+	volumeNameAuthWebhookToken  = "auth-webhook-token"
+	volumeNameOidcWebhookToken  = "oidc-webhook-token"
+	mountPathAuditWebhookToken  = "/var/run/secrets/audit-webhook-token"
+	mountPathAuthWebhookToken   = "/var/run/secrets/auth-webhook-token"
+	mountPathOidcWebhookToken   = "/var/run/secrets/oidc-webhook-token"
+
+	volumeNameKMSPluginSocket           = "kms-plugin-socket"
+	kmsPluginSocketDir                  = "/var/run/kms-plugin"
+	kmsPluginSidecarContainerNamePrefix = "kms-plugin"
 )
 
 func (o *operation) deleteDeployments(ctx context.Context) error {
@@ -46,7 +72,12 @@ func (o *operation) deleteDeployments(ctx context.Context) error {
 			return err
 		}
 	}
-	return nil
+
+	if err := o.deleteWorkloadPolicies(ctx, KubeAPIServerPodDisruptionBudgetName, KubeAPIServerVerticalPodAutoscalerName); err != nil {
+		return err
+	}
+
+	return o.deleteWorkloadPolicies(ctx, KubeControllerManagerPodDisruptionBudgetName, KubeControllerManagerVerticalPodAutoscalerName)
 }
 
 func (o *operation) deployKubeAPIServerDeployment(ctx context.Context, checksums map[string]string, staticTokenHealthCheck string) error {
@@ -63,8 +94,13 @@ func (o *operation) deployKubeAPIServerDeployment(ctx context.Context, checksums
 
 	command := o.getAPIServerCommand()
 
+	volumes, err := o.getAPIServerVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
 	// create/update
-	_, err := controllerutil.CreateOrUpdate(ctx, o.client, deployment, func() error {
+	_, err = controllerutil.CreateOrUpdate(ctx, o.client, deployment, func() error {
 		deployment.ObjectMeta.Labels = kubeAPIServerLabels()
 
 		deployment.Spec = appsv1.DeploymentSpec{
@@ -93,6 +129,7 @@ func (o *operation) deployKubeAPIServerDeployment(ctx context.Context, checksums
 				},
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
+						NodeAffinity: o.getHostCACertsNodeAffinity(),
 						PodAntiAffinity: &corev1.PodAntiAffinity{
 							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
 								{
@@ -121,7 +158,7 @@ func (o *operation) deployKubeAPIServerDeployment(ctx context.Context, checksums
 					AutomountServiceAccountToken: pointer.BoolPtr(false),
 					ServiceAccountName:           KubeAPIServerServiceName,
 					PriorityClassName:            o.imports.VirtualGarden.PriorityClassName,
-					Containers: []corev1.Container{
+					Containers: append([]corev1.Container{
 						{
 							Name:            kubeAPIServerContainerName,
 							Image:           o.imageRefs.KubeAPIServerImage,
@@ -184,23 +221,31 @@ func (o *operation) deployKubeAPIServerDeployment(ctx context.Context, checksums
 								},
 							},
 							VolumeMounts: o.getAPIServerVolumeMounts(),
-						}, // end first and only container
-					}, // end Containers
+						}, // end kube-apiserver container
+					}, append(o.getAuditLogSidecarContainers(), o.getKMSPluginSidecarContainers()...)...), // end Containers
 					DNSPolicy:                     corev1.DNSClusterFirst,
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(30),
-					Volumes:                       o.getAPIServerVolumes(),
+					Volumes:                       volumes,
 				},
 			},
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return o.ensureWorkloadPolicies(ctx, deployment, workloadPolicyOptions(
+		KubeAPIServerPodDisruptionBudgetName,
+		KubeAPIServerVerticalPodAutoscalerName,
+		kubeAPIServerLabels(),
+		apiServerImports.WorkloadPolicy,
+	))
 }
 
 func (o *operation) computeApiServerAnnotations(checksums map[string]string) map[string]string {
-	annotations := o.addChecksumsToAnnotations(checksums, []string{
+	keys := []string{
 		ChecksumKeyKubeAPIServerAuditPolicyConfig,
 		ChecksumKeyKubeAPIServerEncryptionConfig,
 		ChecksumKeyKubeAggregatorCA,
@@ -212,7 +257,10 @@ func (o *operation) computeApiServerAnnotations(checksums map[string]string) map
 		ChecksumKeyKubeAPIServerOidcAuthenticationWebhookConfig,
 		ChecksumKeyKubeAPIServerStaticToken,
 		ChecksumKeyKubeAPIServerAdmissionConfig,
-	})
+	}
+	keys = append(keys, o.getSNIChecksumKeys()...)
+
+	annotations := o.addChecksumsToAnnotations(checksums, keys)
 	return annotations
 }
 
@@ -238,8 +286,37 @@ func (o *operation) getAPIServerCommand() []string {
 	command = append(command, "--allow-privileged=true")
 	command = append(command, "--anonymous-auth=false")
 	command = append(command, "--audit-policy-file=/etc/kube-apiserver/audit/audit-policy.yaml")
-	if o.getAuditWebhookBatchMaxSize() != "" {
-		command = append(command, fmt.Sprintf("--audit-webhook-batch-max-size=%s", o.getAuditWebhookBatchMaxSize()))
+	if logBackend := o.getAuditLogBackend(); logBackend != nil {
+		command = append(command, "--audit-log-path=/var/lib/audit/audit.log")
+		if logBackend.MaxSize != nil {
+			command = append(command, fmt.Sprintf("--audit-log-maxsize=%d", *logBackend.MaxSize))
+		}
+		if logBackend.MaxBackup != nil {
+			command = append(command, fmt.Sprintf("--audit-log-maxbackup=%d", *logBackend.MaxBackup))
+		}
+		if logBackend.MaxAge != nil {
+			command = append(command, fmt.Sprintf("--audit-log-maxage=%d", *logBackend.MaxAge))
+		}
+		if logBackend.Format != "" {
+			command = append(command, fmt.Sprintf("--audit-log-format=%s", logBackend.Format))
+		}
+	}
+	if webhookBackend := o.getAuditWebhookBackend(); webhookBackend != nil {
+		if webhookBackend.BatchMaxSize != "" {
+			command = append(command, fmt.Sprintf("--audit-webhook-batch-max-size=%s", webhookBackend.BatchMaxSize))
+		}
+		if webhookBackend.BatchBufferSize != "" {
+			command = append(command, fmt.Sprintf("--audit-webhook-batch-buffer-size=%s", webhookBackend.BatchBufferSize))
+		}
+		if webhookBackend.BatchMaxWait != "" {
+			command = append(command, fmt.Sprintf("--audit-webhook-batch-max-wait=%s", webhookBackend.BatchMaxWait))
+		}
+		if webhookBackend.BatchThrottleQPS != "" {
+			command = append(command, fmt.Sprintf("--audit-webhook-batch-throttle-qps=%s", webhookBackend.BatchThrottleQPS))
+		}
+		if webhookBackend.Mode != "" {
+			command = append(command, fmt.Sprintf("--audit-webhook-mode=%s", webhookBackend.Mode))
+		}
 	}
 	if len(o.getAPIServerAuditWebhookConfig()) > 0 {
 		command = append(command, "--audit-webhook-config-file=/etc/kube-apiserver/auditwebhook/audit-webhook-config.yaml")
@@ -283,12 +360,7 @@ func (o *operation) getAPIServerCommand() []string {
 		o.imports.VirtualGarden.KubeAPIServer.GetMaxMutatingRequestsInflight(400)))
 	command = append(command, fmt.Sprintf("--max-requests-inflight=%d",
 		o.imports.VirtualGarden.KubeAPIServer.GetMaxRequestsInflight(800)))
-	if o.getAPIServerOIDCIssuerURL() != nil {
-		command = append(command, "--oidc-client-id=kube-kubectl")
-		command = append(command, "--oidc-groups-claim=groups")
-		command = append(command, fmt.Sprintf("--oidc-issuer-url=%s", *o.getAPIServerOIDCIssuerURL()))
-		command = append(command, "--oidc-username-claim=email")
-	}
+	command = append(command, o.getAuthenticationCommand()...)
 	command = append(command, fmt.Sprintf("--profiling=%t", o.imports.VirtualGarden.KubeAPIServer.Profiling))
 	command = append(command, "--proxy-client-cert-file=/srv/kubernetes/aggregator/tls.crt")
 	command = append(command, "--proxy-client-key-file=/srv/kubernetes/aggregator/tls.key")
@@ -305,24 +377,205 @@ func (o *operation) getAPIServerCommand() []string {
 	command = append(command, "--tls-cert-file=/srv/kubernetes/apiserver/tls.crt")
 	command = append(command, "--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,TLS_RSA_WITH_AES_128_CBC_SHA,TLS_RSA_WITH_AES_256_CBC_SHA,TLS_RSA_WITH_AES_128_GCM_SHA256,TLS_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA")
 	command = append(command, "--tls-private-key-file=/srv/kubernetes/apiserver/tls.key")
-	if o.isSNIEnabled() {
-		command = append(command, fmt.Sprintf("--tls-sni-cert-key=/srv/kubernetes/sni-tls/tls.crt,/srv/kubernetes/sni-tls/tls.key:%s", o.getSNIHostname()))
+	for i, sni := range o.getSNIEntries() {
+		command = append(command, fmt.Sprintf("--tls-sni-cert-key=%s/tls.crt,%s/tls.key:%s",
+			sniVolumeMountPath(i), sniVolumeMountPath(i), strings.Join(sni.Hostnames, ",")))
 	}
 	command = append(command, "--token-auth-file=/srv/kubernetes/token/static_tokens.csv")
 	command = append(command, "--v=2")
 	command = append(command, "--watch-cache-sizes=secrets#500,configmaps#500")
 
-	return command
+	if featureGatesFlag := o.getAPIServerFeatureGatesFlag(); featureGatesFlag != "" {
+		command = append(command, featureGatesFlag)
+	}
+
+	return mergeExtraArgs(command, o.imports.VirtualGarden.KubeAPIServer.ExtraArgs)
+}
+
+// getAPIServerFeatureGatesFlag renders imports.VirtualGarden.KubeAPIServer.FeatureGates into a single
+// `--feature-gates=` flag with its gates sorted by name so the flag is deterministic across reconciles.
+func (o *operation) getAPIServerFeatureGatesFlag() string {
+	return featureGatesFlag(o.imports.VirtualGarden.KubeAPIServer.FeatureGates)
+}
+
+// featureGatesFlag renders a feature-gates map into a single `--feature-gates=` flag with its gates sorted by
+// name so the flag is deterministic across reconciles. It returns the empty string if featureGates is empty.
+func featureGatesFlag(featureGates map[string]bool) string {
+	if len(featureGates) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(featureGates))
+	for name := range featureGates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, featureGates[name]))
+	}
+
+	return fmt.Sprintf("--feature-gates=%s", strings.Join(pairs, ","))
+}
+
+// mergeExtraArgs merges extraArgs into command, a list of `--flag=value` (or bare `--flag`) command-line
+// arguments. An entry in extraArgs whose flag name already occurs in command replaces every occurrence of that
+// flag; otherwise it is added. Flags that occur more than once in command (e.g. one --tls-sni-cert-key per SNI
+// entry) and are not overridden keep all their occurrences, in their original order. The first element of
+// command (the binary path) is left untouched. If extraArgs is empty, command is returned unchanged; otherwise
+// the result is sorted by flag name so the rendered command - and therefore the deployment - doesn't churn
+// between reconciles when map iteration order differs.
+func mergeExtraArgs(command []string, extraArgs map[string]string) []string {
+	if len(extraArgs) == 0 {
+		return command
+	}
+
+	binary := command[0]
+	flags := make(map[string][]string, len(command)-1+len(extraArgs))
+	for _, arg := range command[1:] {
+		name := flagName(arg)
+		flags[name] = append(flags[name], arg)
+	}
+
+	for name, value := range extraArgs {
+		flags[name] = []string{fmt.Sprintf("--%s=%s", name, value)}
+	}
+
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make([]string, 0, len(command)+len(extraArgs))
+	merged = append(merged, binary)
+	for _, name := range names {
+		merged = append(merged, flags[name]...)
+	}
+
+	return merged
+}
+
+// flagName extracts the flag name (without leading dashes or "=value") from a command-line argument.
+func flagName(arg string) string {
+	trimmed := strings.TrimPrefix(arg, "--")
+	if idx := strings.Index(trimmed, "="); idx >= 0 {
+		return trimmed[:idx]
+	}
+	return trimmed
 }
 
 func (o *operation) getAPIServerAuditWebhookConfig() string {
+	if webhookBackend := o.getAuditWebhookBackend(); webhookBackend != nil {
+		return webhookBackend.Config
+	}
 	return o.imports.VirtualGarden.KubeAPIServer.AuditWebhookConfig.Config
 }
 
-func (o *operation) getAuditWebhookBatchMaxSize() string {
+// getAuditWebhookBackend returns the configured audit webhook backend, preferring the new
+// KubeAPIServer.Audit.WebhookBackend section over the deprecated top-level fields.
+func (o *operation) getAuditWebhookBackend() *api.AuditWebhookBackend {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+	if apiServer.Audit != nil && apiServer.Audit.WebhookBackend != nil {
+		return apiServer.Audit.WebhookBackend
+	}
+	if apiServer.AuditWebhookConfig.Config == "" && apiServer.AuditWebhookBatchMaxSize == "" {
+		return nil
+	}
 	// comes from landscape.yaml
 	// components.gardener.controlplane.values.apiserver.audit.webhook.batchMaxSize: "30"
-	return o.imports.VirtualGarden.KubeAPIServer.AuditWebhookBatchMaxSize
+	return &api.AuditWebhookBackend{
+		Config:       apiServer.AuditWebhookConfig.Config,
+		BatchMaxSize: apiServer.AuditWebhookBatchMaxSize,
+	}
+}
+
+// getAuditLogBackend returns the configured audit log-file backend, if any.
+func (o *operation) getAuditLogBackend() *api.AuditLogBackend {
+	apiServer := o.imports.VirtualGarden.KubeAPIServer
+	if apiServer.Audit == nil {
+		return nil
+	}
+	return apiServer.Audit.LogBackend
+}
+
+// getAuditLogSidecarContainers returns the sidecar shipping the audit log file off the shared emptyDir volume,
+// when a log backend with a sidecar image is configured. It is empty otherwise, so the audit log is simply
+// written to the emptyDir without being shipped anywhere.
+func (o *operation) getAuditLogSidecarContainers() []corev1.Container {
+	logBackend := o.getAuditLogBackend()
+	if logBackend == nil || logBackend.Sidecar == nil {
+		return nil
+	}
+
+	return []corev1.Container{
+		{
+			Name:            auditLogSidecarContainerName,
+			Image:           logBackend.Sidecar.Image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/bin/sh", "-c", "tail -n+1 -F /var/lib/audit/audit.log"},
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("100Mi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("20Mi"),
+				},
+			},
+			TerminationMessagePath:   "/dev/termination-log",
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      volumeNameKubeAPIServerAuditLog,
+					MountPath: "/var/lib/audit",
+					ReadOnly:  true,
+				},
+			},
+		},
+	}
+}
+
+// getKMSPluginSidecarContainers returns one sidecar per configured KMS encryption provider that carries an
+// Image, running the plugin against the unix socket shared with the kube-apiserver container via
+// volumeNameKMSPluginSocket. Providers without an Image are assumed to be served by a plugin running outside
+// this Pod (e.g. a DaemonSet) and get no sidecar.
+func (o *operation) getKMSPluginSidecarContainers() []corev1.Container {
+	var containers []corev1.Container
+
+	for _, kms := range o.getKMSEncryptionProviders() {
+		if kms.Image == "" {
+			continue
+		}
+
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("%s-%s", kmsPluginSidecarContainerNamePrefix, kms.Name),
+			Image:           kms.Image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("100Mi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("20Mi"),
+				},
+			},
+			TerminationMessagePath:   "/dev/termination-log",
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      volumeNameKMSPluginSocket,
+					MountPath: kmsPluginSocketDir,
+				},
+			},
+		})
+	}
+
+	return containers
 }
 
 func (o *operation) isSeedAuthorizerEnabled() bool {
@@ -333,6 +586,47 @@ func (o *operation) isOidcWebhookAuthenticatorEnabled() bool {
 	return o.imports.VirtualGarden.KubeAPIServer != nil && o.imports.VirtualGarden.KubeAPIServer.OidcWebhookAuthenticator.Enabled
 }
 
+// isAuditWebhookTokenProjectionEnabled reports whether the audit webhook client should authenticate with a
+// projected, audience-bound service account token instead of the static credential embedded in its kubeconfig.
+func (o *operation) isAuditWebhookTokenProjectionEnabled() bool {
+	webhookBackend := o.getAuditWebhookBackend()
+	return webhookBackend != nil && webhookBackend.Token.Enabled
+}
+
+// isAuthWebhookTokenProjectionEnabled reports whether a projected, audience-bound service account token volume
+// is mounted for the seed-authorizer webhook client. The seed-authorizer kubeconfig secret is mounted as it is
+// and is not rewritten to reference that token.
+func (o *operation) isAuthWebhookTokenProjectionEnabled() bool {
+	return o.isSeedAuthorizerEnabled() && o.imports.VirtualGarden.KubeAPIServer.SeedAuthorizer.Token.Enabled
+}
+
+// isOidcWebhookTokenProjectionEnabled reports whether the OIDC webhook authenticator client should
+// authenticate with a projected, audience-bound service account token instead of a static credential.
+func (o *operation) isOidcWebhookTokenProjectionEnabled() bool {
+	return o.isOidcWebhookAuthenticatorEnabled() && o.imports.VirtualGarden.KubeAPIServer.OidcWebhookAuthenticator.Token.Enabled
+}
+
+// projectedServiceAccountTokenVolume builds a projected volume containing a single audience-bound service
+// account token, to be referenced as `tokenFile:` from a webhook kubeconfig.
+func projectedServiceAccountTokenVolume(name string, token api.ProjectedServiceAccountToken) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          token.Audience,
+							ExpirationSeconds: pointer.Int64Ptr(token.ExpirationSeconds),
+							Path:              "token",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func (o *operation) hasEncryptionConfig() bool {
 	return true
 }
@@ -345,18 +639,6 @@ func (o *operation) getAPIServerEventTTL() string {
 	return *o.imports.VirtualGarden.KubeAPIServer.EventTTL
 }
 
-func (o *operation) getAPIServerOIDCIssuerURL() *string {
-	return o.imports.VirtualGarden.KubeAPIServer.OidcIssuerURL
-}
-
-func (o *operation) isSNIEnabled() bool {
-	return o.imports.VirtualGarden.KubeAPIServer.SNI != nil
-}
-
-func (o *operation) getSNIHostname() string {
-	return o.imports.VirtualGarden.KubeAPIServer.SNI.Hostname
-}
-
 func (o *operation) getAPIServerHeaders(staticTokenHealthCheck string) []corev1.HTTPHeader {
 	return []corev1.HTTPHeader{
 		{
@@ -379,6 +661,21 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 			Name:      volumeNameKubeAPIServerAuditWebhookConfig,
 			MountPath: "/etc/kube-apiserver/auditwebhook",
 		})
+
+		if o.isAuditWebhookTokenProjectionEnabled() {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      volumeNameAuditWebhookToken,
+				MountPath: mountPathAuditWebhookToken,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	if o.getAuditLogBackend() != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      volumeNameKubeAPIServerAuditLog,
+			MountPath: "/var/lib/audit",
+		})
 	}
 
 	if o.hasEncryptionConfig() {
@@ -386,6 +683,13 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 			Name:      volumeNameKubeAPIServerEncryptionConfig,
 			MountPath: "/etc/kube-apiserver/encryption",
 		})
+
+		if o.hasKMSEncryptionProvider() {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      volumeNameKMSPluginSocket,
+				MountPath: kmsPluginSocketDir,
+			})
+		}
 	}
 
 	if o.isSeedAuthorizerEnabled() {
@@ -393,6 +697,14 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 			Name:      volumeNameKubeAPIServerAuthWebhookConfig,
 			MountPath: "/etc/kube-apiserver/auth-webhook",
 		})
+
+		if o.isAuthWebhookTokenProjectionEnabled() {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      volumeNameAuthWebhookToken,
+				MountPath: mountPathAuthWebhookToken,
+				ReadOnly:  true,
+			})
+		}
 	}
 
 	if o.isOidcWebhookAuthenticatorEnabled() {
@@ -400,8 +712,18 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 			Name:      volumeNameKubeAPIServerOidcAuthenticationWebhookConfig,
 			MountPath: "/etc/kube-apiserver/authentication-webhook",
 		})
+
+		if o.isOidcWebhookTokenProjectionEnabled() {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      volumeNameOidcWebhookToken,
+				MountPath: mountPathOidcWebhookToken,
+				ReadOnly:  true,
+			})
+		}
 	}
 
+	volumeMounts = append(volumeMounts, o.getAuthenticationVolumeMounts()...)
+
 	volumeMounts = append(volumeMounts,
 		corev1.VolumeMount{
 			Name:      volumeNameKubeAPIServerCA,
@@ -437,34 +759,14 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 		},
 	)
 
-	if o.isSNIEnabled() {
+	for i := range o.getSNIEntries() {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      volumeNameSNITLS,
-			MountPath: "/srv/kubernetes/sni-tls",
+			Name:      sniVolumeName(i),
+			MountPath: sniVolumeMountPath(i),
 		})
 	}
 
-	// locations are taken from
-	// https://github.com/golang/go/blob/1bb247a469e306c57a5e0eaba788efb8b3b1acef/src/crypto/x509/root_linux.go#L7-L15
-	// we cannot be sure on which Node OS the Seed Cluster is running so, it's safer to mount them all
-
-	volumeMounts = append(volumeMounts,
-		corev1.VolumeMount{
-			Name:      volumeNameFedora,
-			MountPath: "/etc/pki/tls",
-			ReadOnly:  true,
-		},
-		corev1.VolumeMount{
-			Name:      volumeNameCentos,
-			MountPath: "/etc/pki/ca-trust/extracted/pem",
-			ReadOnly:  true,
-		},
-		corev1.VolumeMount{
-			Name:      volumeNameETCSSL,
-			MountPath: "/etc/ssl",
-			ReadOnly:  true,
-		},
-	)
+	volumeMounts = append(volumeMounts, o.getHostCACertsVolumeMounts()...)
 
 	if o.isWebhookEnabled() {
 		volumeMounts = append(volumeMounts,
@@ -488,25 +790,74 @@ func (o *operation) getAPIServerVolumeMounts() []corev1.VolumeMount {
 	return volumeMounts
 }
 
-func (o *operation) getAPIServerVolumes() []corev1.Volume {
+// getAPIServerVolumes assembles the kube-apiserver pod's volumes. For secrets managed by secretsmanager
+// (encryption config, audit webhook config, OIDC authentication webhook config, and the secrets from
+// getAuthenticationVolumes), it looks up and mounts the current hashed version rather than the unversioned
+// logical name. The admission kubeconfig is looked up the same way; it is not versioned, so this resolves to
+// its plain name.
+func (o *operation) getAPIServerVolumes(ctx context.Context) ([]corev1.Volume, error) {
 	volumes := []corev1.Volume{}
 
 	if o.hasEncryptionConfig() {
-		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerEncryptionConfig, KubeApiServerSecretNameEncryptionConfig))
+		encryptionConfigSecretName, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameEncryptionConfig)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerEncryptionConfig, encryptionConfigSecretName))
+
+		if o.hasKMSEncryptionProvider() {
+			volumes = append(volumes, corev1.Volume{
+				Name:         volumeNameKMSPluginSocket,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+		}
 	}
 
 	if o.isSeedAuthorizerEnabled() {
 		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerAuthWebhookConfig, KubeApiServerSecretNameAuthWebhookConfig))
+
+		if o.isAuthWebhookTokenProjectionEnabled() {
+			volumes = append(volumes, projectedServiceAccountTokenVolume(volumeNameAuthWebhookToken, o.imports.VirtualGarden.KubeAPIServer.SeedAuthorizer.Token))
+		}
 	}
 
 	if o.isOidcWebhookAuthenticatorEnabled() {
-		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerOidcAuthenticationWebhookConfig, KubeApiServerSecretNameOidcAuthenticationWebhookConfig))
+		oidcAuthenticationWebhookConfigSecretName, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameOidcAuthenticationWebhookConfig)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerOidcAuthenticationWebhookConfig, oidcAuthenticationWebhookConfigSecretName))
+
+		if o.isOidcWebhookTokenProjectionEnabled() {
+			volumes = append(volumes, projectedServiceAccountTokenVolume(volumeNameOidcWebhookToken, o.imports.VirtualGarden.KubeAPIServer.OidcWebhookAuthenticator.Token))
+		}
 	}
 
+	authenticationVolumes, err := o.getAuthenticationVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes = append(volumes, authenticationVolumes...)
+
 	volumes = append(volumes, volumeWithConfigMapSource(volumeNameKubeAPIServerAuditPolicyConfig, KubeApiServerConfigMapAuditPolicy))
 
 	if len(o.getAPIServerAuditWebhookConfig()) > 0 {
-		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerAuditWebhookConfig, KubeApiServerSecretNameAuditWebhookConfig))
+		auditWebhookConfigSecretName, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameAuditWebhookConfig)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volumeWithSecretSource(volumeNameKubeAPIServerAuditWebhookConfig, auditWebhookConfigSecretName))
+
+		if o.isAuditWebhookTokenProjectionEnabled() {
+			volumes = append(volumes, projectedServiceAccountTokenVolume(volumeNameAuditWebhookToken, o.getAuditWebhookBackend().Token))
+		}
+	}
+
+	if o.getAuditLogBackend() != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name:         volumeNameKubeAPIServerAuditLog,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
 	}
 
 	volumes = append(volumes,
@@ -520,14 +871,18 @@ func (o *operation) getAPIServerVolumes() []corev1.Volume {
 		volumeWithSecretSource(volumeNameKubeAggregator, KubeApiServerSecretNameAggregatorClientCertificate),
 	)
 
-	if o.isSNIEnabled() {
-		volumes = append(volumes, volumeWithSecretSource(volumeNameSNITLS, o.imports.VirtualGarden.KubeAPIServer.SNI.SecretName))
+	for i, sni := range o.getSNIEntries() {
+		volumes = append(volumes, volumeWithSecretSource(sniVolumeName(i), sni.SecretName))
 	}
 
 	if o.isWebhookEnabled() {
+		admissionKubeconfigSecretName, err := o.currentKubeAPIServerSecretName(ctx, KubeApiServerSecretNameAdmissionKubeconfig)
+		if err != nil {
+			return nil, err
+		}
 		volumes = append(volumes,
 			volumeWithConfigMapSource(volumeNameKubeAPIServerAdmissionConfig, KubeApiServerConfigMapAdmission),
-			volumeWithSecretSource(volumeNameKubeAPIServerAdmissionKubeconfig, KubeApiServerSecretNameAdmissionKubeconfig),
+			volumeWithSecretSource(volumeNameKubeAPIServerAdmissionKubeconfig, admissionKubeconfigSecretName),
 		)
 
 		projections := []corev1.VolumeProjection{}
@@ -562,46 +917,143 @@ func (o *operation) getAPIServerVolumes() []corev1.Volume {
 		)
 	}
 
-	// locations are taken from
-	// https://github.com/golang/go/blob/1bb247a469e306c57a5e0eaba788efb8b3b1acef/src/crypto/x509/root_linux.go#L7-L15
-	// we cannot be sure on which Node OS the Seed Cluster is running so, it's safer to mount them all
+	volumes = append(volumes, o.getHostCACertsVolumes()...)
 
-	hostPathDirectoryOrCreate := corev1.HostPathDirectoryOrCreate
-	volumes = append(volumes,
-		corev1.Volume{
-			Name: volumeNameFedora,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/etc/pki/tls",
-					Type: &hostPathDirectoryOrCreate,
-				},
+	volumes = append(volumes, o.imports.VirtualGarden.KubeAPIServer.AdditionalVolumes...)
+
+	return volumes, nil
+}
+
+func (o *operation) emptyDeployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.namespace}}
+}
+
+func (o *operation) emptyPodDisruptionBudget(name string) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.namespace}}
+}
+
+func (o *operation) emptyVerticalPodAutoscaler(name string) *autoscalingv1beta2.VerticalPodAutoscaler {
+	return &autoscalingv1beta2.VerticalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.namespace}}
+}
+
+// WorkloadPolicyOptions configures the PodDisruptionBudget and VerticalPodAutoscaler that ensureWorkloadPolicies
+// reconciles for a managed Deployment.
+type WorkloadPolicyOptions struct {
+	// PodDisruptionBudgetName is the name of the PodDisruptionBudget to reconcile.
+	PodDisruptionBudgetName string
+	// VerticalPodAutoscalerName is the name of the VerticalPodAutoscaler to reconcile.
+	VerticalPodAutoscalerName string
+	// Labels are applied to the PodDisruptionBudget/VerticalPodAutoscaler and used as their selector.
+	Labels map[string]string
+	// MinAvailable is the PodDisruptionBudget's minAvailable. Mutually exclusive with MaxUnavailable.
+	MinAvailable *intstr.IntOrString
+	// MaxUnavailable is the PodDisruptionBudget's maxUnavailable.
+	MaxUnavailable *intstr.IntOrString
+	// VPAUpdateMode is the VerticalPodAutoscaler's update mode.
+	VPAUpdateMode autoscalingv1beta2.UpdateMode
+	// VPAMinAllowed is the minimum container resources allowed by the VerticalPodAutoscaler.
+	VPAMinAllowed corev1.ResourceList
+	// VPAMaxAllowed is the maximum container resources allowed by the VerticalPodAutoscaler.
+	VPAMaxAllowed corev1.ResourceList
+}
+
+// workloadPolicyOptions translates a component's (optional) api.WorkloadPolicy into WorkloadPolicyOptions,
+// applying the package defaults of minAvailable 1 and VPA update mode Auto.
+func workloadPolicyOptions(pdbName, vpaName string, labels map[string]string, policy *api.WorkloadPolicy) WorkloadPolicyOptions {
+	opts := WorkloadPolicyOptions{
+		PodDisruptionBudgetName:   pdbName,
+		VerticalPodAutoscalerName: vpaName,
+		Labels:                    labels,
+		VPAUpdateMode:             autoscalingv1beta2.UpdateModeAuto,
+	}
+
+	minAvailable := intstr.FromInt(1)
+	opts.MinAvailable = &minAvailable
+
+	if policy == nil {
+		return opts
+	}
+
+	switch {
+	case policy.MaxUnavailable != nil:
+		maxUnavailable := intstr.FromInt(int(*policy.MaxUnavailable))
+		opts.MinAvailable = nil
+		opts.MaxUnavailable = &maxUnavailable
+	case policy.MinAvailable != nil:
+		minAvailable := intstr.FromInt(int(*policy.MinAvailable))
+		opts.MinAvailable = &minAvailable
+	}
+
+	if policy.VPAUpdateMode != "" {
+		opts.VPAUpdateMode = autoscalingv1beta2.UpdateMode(policy.VPAUpdateMode)
+	}
+	opts.VPAMinAllowed = policy.VPAMinAllowed
+	opts.VPAMaxAllowed = policy.VPAMaxAllowed
+
+	return opts
+}
+
+// ensureWorkloadPolicies idempotently reconciles a PodDisruptionBudget (minAvailable/maxUnavailable) and a
+// VerticalPodAutoscaler (update mode plus per-container min/max allowed resources) targeting dep, per opts.
+func (o *operation) ensureWorkloadPolicies(ctx context.Context, dep *appsv1.Deployment, opts WorkloadPolicyOptions) error {
+	pdb := o.emptyPodDisruptionBudget(opts.PodDisruptionBudgetName)
+	if _, err := controllerutil.CreateOrUpdate(ctx, o.client, pdb, func() error {
+		pdb.ObjectMeta.Labels = opts.Labels
+		pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   opts.MinAvailable,
+			MaxUnavailable: opts.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: opts.Labels,
 			},
-		},
-		corev1.Volume{
-			Name: volumeNameCentos,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/etc/pki/ca-trust/extracted/pem",
-					Type: &hostPathDirectoryOrCreate,
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	vpa := o.emptyVerticalPodAutoscaler(opts.VerticalPodAutoscalerName)
+	updateMode := opts.VPAUpdateMode
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.client, vpa, func() error {
+		var resourcePolicy *autoscalingv1beta2.PodResourcePolicy
+		if len(opts.VPAMinAllowed) > 0 || len(opts.VPAMaxAllowed) > 0 {
+			resourcePolicy = &autoscalingv1beta2.PodResourcePolicy{
+				ContainerPolicies: []autoscalingv1beta2.ContainerResourcePolicy{
+					{
+						ContainerName: "*",
+						MinAllowed:    opts.VPAMinAllowed,
+						MaxAllowed:    opts.VPAMaxAllowed,
+					},
 				},
+			}
+		}
+
+		vpa.ObjectMeta.Labels = opts.Labels
+		vpa.Spec = autoscalingv1beta2.VerticalPodAutoscalerSpec{
+			TargetRef: &autoscalingv1.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       dep.Name,
 			},
-		},
-		corev1.Volume{
-			Name: volumeNameETCSSL,
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/etc/ssl",
-					Type: &hostPathDirectoryOrCreate,
-				},
+			UpdatePolicy: &autoscalingv1beta2.PodUpdatePolicy{
+				UpdateMode: &updateMode,
 			},
-		},
-	)
-
-	volumes = append(volumes, o.imports.VirtualGarden.KubeAPIServer.AdditionalVolumes...)
+			ResourcePolicy: resourcePolicy,
+		}
+		return nil
+	})
 
-	return volumes
+	return err
 }
 
-func (o *operation) emptyDeployment(name string) *appsv1.Deployment {
-	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.namespace}}
+// deleteWorkloadPolicies deletes the PodDisruptionBudget and VerticalPodAutoscaler reconciled by
+// ensureWorkloadPolicies for the given names.
+func (o *operation) deleteWorkloadPolicies(ctx context.Context, pdbName, vpaName string) error {
+	vpa := o.emptyVerticalPodAutoscaler(vpaName)
+	if err := o.client.Delete(ctx, vpa); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	pdb := o.emptyPodDisruptionBudget(pdbName)
+	return client.IgnoreNotFound(o.client.Delete(ctx, pdb))
 }
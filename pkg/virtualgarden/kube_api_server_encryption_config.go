@@ -0,0 +1,247 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	cryptorand "crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1 "k8s.io/apiserver/pkg/apis/config/v1"
+
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+// getEncryptionConfig returns imports.VirtualGarden.KubeAPIServer.EncryptionConfig, defaulting to a single
+// AES-CBC provider (plus an identity fallback) for secrets when unset, matching the historical behavior.
+func (o *operation) getEncryptionConfig() *api.EncryptionConfig {
+	if config := o.imports.VirtualGarden.KubeAPIServer.EncryptionConfig; config != nil {
+		return config
+	}
+	return &api.EncryptionConfig{
+		Resources: []api.EncryptionResourceConfig{
+			{
+				Resources: []string{"secrets"},
+				Providers: []api.EncryptionProviderConfig{
+					{AESCBC: &api.EncryptionKeyProviderConfig{}},
+					{Identity: &api.EncryptionIdentityProviderConfig{}},
+				},
+			},
+		},
+	}
+}
+
+// hasKMSEncryptionProvider reports whether any resource in the encryption config uses a KMS provider.
+func (o *operation) hasKMSEncryptionProvider() bool {
+	for _, resourceConfig := range o.getEncryptionConfig().Resources {
+		for _, providerConfig := range resourceConfig.Providers {
+			if providerConfig.KMS != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getKMSEncryptionProviders returns every KMS provider configured across all resources.
+func (o *operation) getKMSEncryptionProviders() []api.EncryptionKMSProviderConfig {
+	var providers []api.EncryptionKMSProviderConfig
+	for _, resourceConfig := range o.getEncryptionConfig().Resources {
+		for _, providerConfig := range resourceConfig.Providers {
+			if providerConfig.KMS != nil {
+				providers = append(providers, *providerConfig.KMS)
+			}
+		}
+	}
+	return providers
+}
+
+// generateEncryptionConfig renders imports.VirtualGarden.KubeAPIServer.EncryptionConfig into the
+// EncryptionConfiguration consumed by the kube-apiserver's --encryption-provider-config flag. Generated keys
+// (aescbc/aesgcm/secretbox) are carried over from existing, unless RotateNow is set, in which case a fresh
+// primary key is generated and prepended while the previous key(s) are kept so existing data stays decryptable.
+func (o *operation) generateEncryptionConfig(existing []byte) ([]byte, error) {
+	desired := o.getEncryptionConfig()
+
+	if err := validateEncryptionConfig(desired); err != nil {
+		return nil, fmt.Errorf("invalid encryption config: %w", err)
+	}
+
+	var previous *configv1.EncryptionConfiguration
+	if len(existing) > 0 {
+		previous = &configv1.EncryptionConfiguration{}
+		if err := yaml.Unmarshal(existing, previous); err != nil {
+			return nil, fmt.Errorf("could not parse existing encryption config: %w", err)
+		}
+	}
+
+	resources := make([]configv1.ResourceConfiguration, 0, len(desired.Resources))
+	for i, resourceConfig := range desired.Resources {
+		var previousProviders []configv1.ProviderConfiguration
+		if previous != nil && i < len(previous.Resources) {
+			previousProviders = previous.Resources[i].Providers
+		}
+
+		providers := make([]configv1.ProviderConfiguration, 0, len(resourceConfig.Providers))
+		for j, providerConfig := range resourceConfig.Providers {
+			var previousProvider *configv1.ProviderConfiguration
+			if j < len(previousProviders) {
+				previousProvider = &previousProviders[j]
+			}
+
+			provider, err := buildProviderConfiguration(providerConfig, previousProvider, desired.RotateNow)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		}
+
+		resources = append(resources, configv1.ResourceConfiguration{
+			Resources: resourceConfig.Resources,
+			Providers: providers,
+		})
+	}
+
+	encryptionConfig := configv1.EncryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "EncryptionConfiguration",
+		},
+		Resources: resources,
+	}
+
+	return yaml.Marshal(&encryptionConfig)
+}
+
+// validateEncryptionConfig rejects encryption configs that would leave data unprotected (a resource whose first
+// provider is identity, so writes are never encrypted) or ambiguous (two KMS providers sharing the same name).
+func validateEncryptionConfig(config *api.EncryptionConfig) error {
+	kmsNames := map[string]bool{}
+
+	for _, resourceConfig := range config.Resources {
+		if len(resourceConfig.Providers) > 0 && resourceConfig.Providers[0].Identity != nil {
+			return fmt.Errorf("resource %v: first provider must not be identity, as it would leave writes unencrypted", resourceConfig.Resources)
+		}
+
+		for _, providerConfig := range resourceConfig.Providers {
+			if providerConfig.KMS == nil {
+				continue
+			}
+			if kmsNames[providerConfig.KMS.Name] {
+				return fmt.Errorf("resource %v: duplicate KMS provider name %q", resourceConfig.Resources, providerConfig.KMS.Name)
+			}
+			kmsNames[providerConfig.KMS.Name] = true
+		}
+	}
+
+	return nil
+}
+
+func buildProviderConfiguration(providerConfig api.EncryptionProviderConfig, previous *configv1.ProviderConfiguration, rotateNow bool) (configv1.ProviderConfiguration, error) {
+	switch {
+	case providerConfig.Identity != nil:
+		return configv1.ProviderConfiguration{Identity: &configv1.IdentityConfiguration{}}, nil
+
+	case providerConfig.KMS != nil:
+		kms := providerConfig.KMS
+		return configv1.ProviderConfiguration{
+			KMS: &configv1.KMSConfiguration{
+				Name:      kms.Name,
+				Endpoint:  kms.Endpoint,
+				CacheSize: kms.CacheSize,
+				Timeout:   kmsTimeout(kms.Timeout),
+			},
+		}, nil
+
+	case providerConfig.AESCBC != nil:
+		keys, err := resolveEncryptionKeys(previousKeys(previous), rotateNow)
+		if err != nil {
+			return configv1.ProviderConfiguration{}, err
+		}
+		return configv1.ProviderConfiguration{AESCBC: &configv1.AESConfiguration{Keys: keys}}, nil
+
+	case providerConfig.AESGCM != nil:
+		keys, err := resolveEncryptionKeys(previousKeys(previous), rotateNow)
+		if err != nil {
+			return configv1.ProviderConfiguration{}, err
+		}
+		return configv1.ProviderConfiguration{AESGCM: &configv1.AESConfiguration{Keys: keys}}, nil
+
+	case providerConfig.Secretbox != nil:
+		keys, err := resolveEncryptionKeys(previousKeys(previous), rotateNow)
+		if err != nil {
+			return configv1.ProviderConfiguration{}, err
+		}
+		return configv1.ProviderConfiguration{Secretbox: &configv1.SecretboxConfiguration{Keys: keys}}, nil
+
+	default:
+		return configv1.ProviderConfiguration{}, fmt.Errorf("encryption provider config has no provider set")
+	}
+}
+
+// previousKeys extracts the generated keys from whichever provider was previously configured at this position,
+// regardless of which of aescbc/aesgcm/secretbox it was, so a change between these types carries the keys over.
+// An identity or kms provider (or none) at this position yields no previous keys.
+func previousKeys(previous *configv1.ProviderConfiguration) []configv1.Key {
+	if previous == nil {
+		return nil
+	}
+	switch {
+	case previous.AESCBC != nil:
+		return previous.AESCBC.Keys
+	case previous.AESGCM != nil:
+		return previous.AESGCM.Keys
+	case previous.Secretbox != nil:
+		return previous.Secretbox.Keys
+	default:
+		return nil
+	}
+}
+
+func resolveEncryptionKeys(previous []configv1.Key, rotateNow bool) ([]configv1.Key, error) {
+	if len(previous) > 0 && !rotateNow {
+		return previous, nil
+	}
+
+	newKey, err := generateEncryptionKey(fmt.Sprintf("key-%d", len(previous)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]configv1.Key{newKey}, previous...), nil
+}
+
+func generateEncryptionKey(name string) (configv1.Key, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := cryptorand.Read(secretBytes); err != nil {
+		return configv1.Key{}, err
+	}
+
+	return configv1.Key{Name: name, Secret: utils.EncodeBase64(secretBytes)}, nil
+}
+
+func kmsTimeout(timeout *string) *metav1.Duration {
+	if timeout == nil {
+		return nil
+	}
+	duration, err := time.ParseDuration(*timeout)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Duration{Duration: duration}
+}
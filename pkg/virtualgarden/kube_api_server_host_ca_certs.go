@@ -0,0 +1,144 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+const (
+	volumeNameFedora            = "fedora-ca-certs"
+	volumeNameCentos            = "centos-ca-certs"
+	volumeNameETCSSL            = "etc-ssl-ca-certs"
+	volumeNameHostCACertsBundle = "host-ca-certs-bundle"
+
+	hostCACertsBundleMountPath = "/etc/ssl/certs/ca-certificates.crt"
+	hostCACertsBundleDataKey   = "ca-certificates.crt"
+)
+
+// getHostCACertsMode returns the configured HostCACerts.Mode, defaulting to the deprecated
+// HostCACertsModeBundleAllHostPaths for back-compat when unset.
+func (o *operation) getHostCACertsMode() api.HostCACertsMode {
+	hostCACerts := o.imports.VirtualGarden.KubeAPIServer.HostCACerts
+	if hostCACerts == nil || hostCACerts.Mode == "" {
+		return api.HostCACertsModeBundleAllHostPaths
+	}
+	return hostCACerts.Mode
+}
+
+// getHostCACertsVolumeMounts returns the volume mounts needed to expose the host's CA trust store to the
+// kube-apiserver container, depending on the configured HostCACertsMode.
+func (o *operation) getHostCACertsVolumeMounts() []corev1.VolumeMount {
+	switch o.getHostCACertsMode() {
+	case api.HostCACertsModeNone:
+		return nil
+	case api.HostCACertsModeBundle:
+		return []corev1.VolumeMount{
+			{
+				Name:      volumeNameHostCACertsBundle,
+				MountPath: hostCACertsBundleMountPath,
+				SubPath:   hostCACertsBundleDataKey,
+				ReadOnly:  true,
+			},
+		}
+	case api.HostCACertsModeDebian:
+		return []corev1.VolumeMount{{Name: volumeNameETCSSL, MountPath: "/etc/ssl", ReadOnly: true}}
+	case api.HostCACertsModeFedora:
+		return []corev1.VolumeMount{{Name: volumeNameFedora, MountPath: "/etc/pki/tls", ReadOnly: true}}
+	case api.HostCACertsModeCentos:
+		return []corev1.VolumeMount{{Name: volumeNameCentos, MountPath: "/etc/pki/ca-trust/extracted/pem", ReadOnly: true}}
+	case api.HostCACertsModeAuto, api.HostCACertsModeBundleAllHostPaths:
+		fallthrough
+	default:
+		// locations are taken from
+		// https://github.com/golang/go/blob/1bb247a469e306c57a5e0eaba788efb8b3b1acef/src/crypto/x509/root_linux.go#L7-L15
+		return []corev1.VolumeMount{
+			{Name: volumeNameFedora, MountPath: "/etc/pki/tls", ReadOnly: true},
+			{Name: volumeNameCentos, MountPath: "/etc/pki/ca-trust/extracted/pem", ReadOnly: true},
+			{Name: volumeNameETCSSL, MountPath: "/etc/ssl", ReadOnly: true},
+		}
+	}
+}
+
+// getHostCACertsVolumes returns the pod volumes backing getHostCACertsVolumeMounts.
+func (o *operation) getHostCACertsVolumes() []corev1.Volume {
+	hostPathDirectoryOrCreate := corev1.HostPathDirectoryOrCreate
+
+	switch o.getHostCACertsMode() {
+	case api.HostCACertsModeNone:
+		return nil
+	case api.HostCACertsModeBundle:
+		bundle := o.imports.VirtualGarden.KubeAPIServer.HostCACerts.Bundle
+		if bundle.ConfigMapName != "" {
+			return []corev1.Volume{volumeWithConfigMapSource(volumeNameHostCACertsBundle, bundle.ConfigMapName)}
+		}
+		return []corev1.Volume{volumeWithSecretSource(volumeNameHostCACertsBundle, bundle.SecretName)}
+	case api.HostCACertsModeDebian:
+		return []corev1.Volume{hostPathVolume(volumeNameETCSSL, "/etc/ssl", &hostPathDirectoryOrCreate)}
+	case api.HostCACertsModeFedora:
+		return []corev1.Volume{hostPathVolume(volumeNameFedora, "/etc/pki/tls", &hostPathDirectoryOrCreate)}
+	case api.HostCACertsModeCentos:
+		return []corev1.Volume{hostPathVolume(volumeNameCentos, "/etc/pki/ca-trust/extracted/pem", &hostPathDirectoryOrCreate)}
+	case api.HostCACertsModeAuto, api.HostCACertsModeBundleAllHostPaths:
+		fallthrough
+	default:
+		// we cannot determine the exact trust-store path without an init container inspecting the Node's
+		// filesystem, so as a stopgap we still mount every well-known path - but, unlike the deprecated
+		// BundleAllHostPaths default, Auto at least restricts scheduling to Linux nodes via nodeAffinity below.
+		return []corev1.Volume{
+			hostPathVolume(volumeNameFedora, "/etc/pki/tls", &hostPathDirectoryOrCreate),
+			hostPathVolume(volumeNameCentos, "/etc/pki/ca-trust/extracted/pem", &hostPathDirectoryOrCreate),
+			hostPathVolume(volumeNameETCSSL, "/etc/ssl", &hostPathDirectoryOrCreate),
+		}
+	}
+}
+
+func hostPathVolume(name, path string, pathType *corev1.HostPathType) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: path,
+				Type: pathType,
+			},
+		},
+	}
+}
+
+// getHostCACertsNodeAffinity returns the additional node affinity term required for the configured
+// HostCACertsMode, or nil if none is needed.
+func (o *operation) getHostCACertsNodeAffinity() *corev1.NodeAffinity {
+	if o.getHostCACertsMode() != api.HostCACertsModeAuto {
+		return nil
+	}
+
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "kubernetes.io/os",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"linux"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -16,19 +16,17 @@ package virtualgarden
 
 import (
 	"context"
-	cryptorand "crypto/rand"
-	_ "embed"
 	"fmt"
+	"time"
 
-	"github.com/ghodss/yaml"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	configv1 "k8s.io/apiserver/pkg/apis/config/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/gardener/gardener/pkg/utils"
-	"github.com/gardener/virtual-garden/pkg/util"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/secretsmanager"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/webhookcerts"
 )
 
 const (
@@ -36,13 +34,11 @@ const (
 	KubeApiServerSecretNameAuditWebhookConfig  = "kube-apiserver-audit-webhook-config"
 	KubeApiServerSecretNameBasicAuth           = Prefix + "-kube-apiserver-basic-auth"
 	KubeApiServerSecretNameEncryptionConfig    = Prefix + "-kube-apiserver-encryption-config"
-)
-
-//go:embed resources/validating-webhook-kubeconfig.yaml
-var validatingWebhookKubeconfig []byte
 
-//go:embed resources/mutating-webhook-kubeconfig.yaml
-var mutatingWebhookKubeconfig []byte
+	// encryptionConfigSecretKey is the data key under which KubeApiServerSecretNameEncryptionConfig stores the
+	// rendered EncryptionConfiguration.
+	encryptionConfigSecretKey = "encryption-config.yaml"
+)
 
 func (o *operation) deployKubeAPIServerSecrets(ctx context.Context) error {
 	if err := o.deployKubeApiServerSecretAdmissionKubeconfig(ctx); err != nil {
@@ -53,46 +49,124 @@ func (o *operation) deployKubeAPIServerSecrets(ctx context.Context) error {
 		return err
 	}
 
-	if err := o.deployKubeApiServerSecretBasicAuth(ctx); err != nil {
-		return err
+	if o.isBasicAuthEnabled() {
+		if err := o.deployKubeApiServerSecretBasicAuth(ctx); err != nil {
+			return err
+		}
 	}
 
 	if err := o.deployKubeApiServerSecretEncryptionConfig(ctx); err != nil {
 		return err
 	}
 
-	return nil
+	if err := o.deployKubeApiServerSecretOidcAuthenticationWebhookConfig(ctx); err != nil {
+		return err
+	}
+
+	if err := o.deployKubeApiServerSecretOidcCABundle(ctx); err != nil {
+		return err
+	}
+
+	if err := o.deployKubeApiServerSecretAuthenticationConfig(ctx); err != nil {
+		return err
+	}
+
+	return secretsmanager.Cleanup(ctx, o.client, o.namespace)
 }
 
 func (o *operation) deleteKubeAPIServerSecrets(ctx context.Context) error {
+	if err := client.IgnoreNotFound(o.client.Delete(ctx, o.emptySecret(KubeApiServerSecretNameAdmissionKubeconfig))); err != nil {
+		return fmt.Errorf("could not delete secret %q: %w", KubeApiServerSecretNameAdmissionKubeconfig, err)
+	}
+
+	if err := o.deleteKubeAPIServerWebhookCertificates(ctx); err != nil {
+		return err
+	}
+
 	for _, name := range []string{
-		KubeApiServerSecretNameAdmissionKubeconfig,
 		KubeApiServerSecretNameAuditWebhookConfig,
 		KubeApiServerSecretNameBasicAuth,
 		KubeApiServerSecretNameEncryptionConfig,
+		KubeApiServerSecretNameOidcAuthenticationWebhookConfig,
+		KubeApiServerSecretNameOidcCABundle,
+		KubeApiServerSecretNameAuthenticationConfig,
 	} {
-		secret := o.emptySecret(name)
-		if err := o.client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		if err := o.deleteManagedSecretVersions(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteManagedSecretVersions deletes every version of the secretsmanager-managed logical secret name, regardless
+// of grace period, since the component (or the whole virtual garden) is being torn down.
+func (o *operation) deleteManagedSecretVersions(ctx context.Context, name string) error {
+	var list corev1.SecretList
+	if err := o.client.List(ctx, &list, client.InNamespace(o.namespace), client.MatchingLabels{secretsmanager.LabelKeyName: name}); err != nil {
+		return fmt.Errorf("could not list versions of secret %q: %w", name, err)
+	}
+
+	for i := range list.Items {
+		if err := client.IgnoreNotFound(o.client.Delete(ctx, &list.Items[i])); err != nil {
 			return err
 		}
 	}
+
 	return nil
 }
 
+// rotationGracePeriod returns how long a superseded kube-apiserver secret version is retained, per
+// imports.VirtualGarden.KubeAPIServer.RotationConfig.GracePeriod, defaulting to secretsmanager.DefaultGracePeriod.
+func (o *operation) rotationGracePeriod() time.Duration {
+	rotationConfig := o.imports.VirtualGarden.KubeAPIServer.RotationConfig
+	if rotationConfig == nil || rotationConfig.GracePeriod == "" {
+		return secretsmanager.DefaultGracePeriod
+	}
+
+	gracePeriod, err := time.ParseDuration(rotationConfig.GracePeriod)
+	if err != nil {
+		return secretsmanager.DefaultGracePeriod
+	}
+
+	return gracePeriod
+}
+
+// deployKubeApiServerSecretAdmissionKubeconfig renders the gardener-apiserver admission webhook kubeconfigs from a
+// self-signed CA and per-webhook serving certificate generated (and rotated) by webhookcerts, rather than static,
+// externally-managed kubeconfig files. Unlike the other secrets in this file, it is re-rendered on every reconcile
+// (not secretsmanager-versioned), so it always reflects the current certificate material.
 func (o *operation) deployKubeApiServerSecretAdmissionKubeconfig(ctx context.Context) error {
 	controlplane := o.imports.VirtualGarden.KubeAPIServer.GardenerControlplane
 	if !controlplane.ValidatingWebhookEnabled && !controlplane.MutatingWebhookEnabled {
 		return nil
 	}
 
-	secret := o.emptySecret(KubeApiServerSecretNameAdmissionKubeconfig)
+	ca, caRotated, err := webhookcerts.EnsureCA(ctx, o.client, o.namespace, KubeApiServerSecretNameWebhookCA, o.webhookCertificateRenewalWindow())
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, o.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
+	if controlplane.ValidatingWebhookEnabled {
+		validatingWebhookKubeconfig, err := o.renderWebhookKubeconfig(ctx, validatingWebhookKubeconfigTemplate, kubeApiServerSecretNameWebhookCertValidating, kubeApiServerWebhookClientNameValidatingWebhook, controlplane.ValidatingWebhook, ca, caRotated)
+		if err != nil {
+			return err
 		}
-		secret.Data["validating-webhook"] = validatingWebhookKubeconfig
-		secret.Data["mutating-webhook"] = mutatingWebhookKubeconfig
+		data["validating-webhook"] = validatingWebhookKubeconfig
+	}
+
+	if controlplane.MutatingWebhookEnabled {
+		mutatingWebhookKubeconfig, err := o.renderWebhookKubeconfig(ctx, mutatingWebhookKubeconfigTemplate, kubeApiServerSecretNameWebhookCertMutating, kubeApiServerWebhookClientNameMutatingWebhook, controlplane.MutatingWebhook, ca, caRotated)
+		if err != nil {
+			return err
+		}
+		data["mutating-webhook"] = mutatingWebhookKubeconfig
+	}
+
+	secret := o.emptySecret(KubeApiServerSecretNameAdmissionKubeconfig)
+	_, err = controllerutil.CreateOrUpdate(ctx, o.client, secret, func() error {
+		secret.Data = data
 		return nil
 	})
 
@@ -100,19 +174,25 @@ func (o *operation) deployKubeApiServerSecretAdmissionKubeconfig(ctx context.Con
 }
 
 func (o *operation) deployKubeApiServerSecretAuditWebhookConfig(ctx context.Context) error {
-	config := o.imports.VirtualGarden.KubeAPIServer.AuditWebhookConfig.Config
+	config := o.getAPIServerAuditWebhookConfig()
 	if len(config) == 0 {
 		return nil
 	}
 
-	secret := o.emptySecret(KubeApiServerSecretNameAuditWebhookConfig)
-
-	_, err := controllerutil.CreateOrUpdate(ctx, o.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
+	if o.isAuditWebhookTokenProjectionEnabled() {
+		rewritten, err := rewriteKubeconfigWithTokenFile(config, mountPathAuditWebhookToken+"/token")
+		if err != nil {
+			return fmt.Errorf("could not rewrite audit webhook kubeconfig for projected token auth: %w", err)
 		}
-		secret.Data["audit-webhook-config.yaml"] = []byte(config)
-		return nil
+		config = rewritten
+	}
+
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:        KubeApiServerSecretNameAuditWebhookConfig,
+		GracePeriod: o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{"audit-webhook-config.yaml": []byte(config)}, nil
+		},
 	})
 
 	return err
@@ -121,105 +201,61 @@ func (o *operation) deployKubeApiServerSecretAuditWebhookConfig(ctx context.Cont
 func (o *operation) deployKubeApiServerSecretBasicAuth(ctx context.Context) error {
 	const basicAuthKey = "basic_auth.csv"
 
-	var basicAuthValue []byte
-
-	secret := o.emptySecret(KubeApiServerSecretNameBasicAuth)
-	err := o.client.Get(ctx, util.GetKey(secret), secret)
-	if err != nil {
-		if client.IgnoreNotFound(err) != nil {
-			return err
-		}
-
-		// secret does not exist: generate password
-		pw, err2 := utils.GenerateRandomString(32)
-		if err2 != nil {
-			return err2
-		}
-
-		basicAuthValue = []byte(fmt.Sprintf("%s,admin,admin,system:masters", pw))
-	} else {
-		// secret exists: use existing value
-		basicAuthValue = secret.Data[basicAuthKey]
+	var rotationSeed string
+	if rotationConfig := o.imports.VirtualGarden.KubeAPIServer.RotationConfig; rotationConfig != nil {
+		rotationSeed = rotationConfig.BasicAuth
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, o.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
-		secret.Data[basicAuthKey] = basicAuthValue
-		return nil
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:         KubeApiServerSecretNameBasicAuth,
+		RotationSeed: rotationSeed,
+		GracePeriod:  o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			pw, err := utils.GenerateRandomString(32)
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]byte{basicAuthKey: []byte(fmt.Sprintf("%s,admin,admin,system:masters", pw))}, nil
+		},
 	})
 
 	return err
 }
 
 func (o *operation) deployKubeApiServerSecretEncryptionConfig(ctx context.Context) error {
-	const encryptionConfigKey = "encryption-config.yaml"
-
-	var encryptionConfigValue []byte
-
-	secret := o.emptySecret(KubeApiServerSecretNameEncryptionConfig)
-	err := o.client.Get(ctx, util.GetKey(secret), secret)
-	if err != nil {
-		if client.IgnoreNotFound(err) != nil {
-			return err
-		}
-
-		// secret does not exist: generate encryption config
-		encryptionConfigValue, err = o.generateNewEncryptionConfig()
-		if err != nil {
-			return err
-		}
-	} else {
-		// secret exists: use existing value
-		encryptionConfigValue = secret.Data[encryptionConfigKey]
+	var rotationSeed string
+	if rotationConfig := o.imports.VirtualGarden.KubeAPIServer.RotationConfig; rotationConfig != nil {
+		rotationSeed = rotationConfig.EncryptionKey
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, o.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
-		secret.Data[encryptionConfigKey] = encryptionConfigValue
-		return nil
+	_, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:         KubeApiServerSecretNameEncryptionConfig,
+		RotationSeed: rotationSeed,
+		GracePeriod:  o.rotationGracePeriod(),
+		Generate: func(previous map[string][]byte) (map[string][]byte, error) {
+			encryptionConfigValue, err := o.generateEncryptionConfig(previous[encryptionConfigSecretKey])
+			if err != nil {
+				return nil, err
+			}
+			return map[string][]byte{encryptionConfigSecretKey: encryptionConfigValue}, nil
+		},
 	})
 
 	return err
 }
 
-func (o *operation) generateNewEncryptionConfig() ([]byte, error) {
-	secretBytes := make([]byte, 32)
-	if _, err := cryptorand.Read(secretBytes); err != nil {
-		return nil, err
-	}
-
-	secretString := utils.EncodeBase64(secretBytes)
-
-	encryptionConfig := configv1.EncryptionConfiguration{
-		Resources: []configv1.ResourceConfiguration{
-			{
-				Resources: []string{
-					"secrets",
-				},
-				Providers: []configv1.ProviderConfiguration{
-					{
-						AESCBC: &configv1.AESConfiguration{
-							Keys: []configv1.Key{
-								{
-									Name:   "key",
-									Secret: secretString,
-								},
-							},
-						},
-					},
-					{
-						Identity: &configv1.IdentityConfiguration{},
-					},
-				},
-			},
-		},
+// currentKubeAPIServerSecretName returns the name of the current version of the secretsmanager-managed logical
+// secret name, falling back to name itself if no version has been generated yet (e.g. the resource is optional
+// and was never deployed).
+func (o *operation) currentKubeAPIServerSecretName(ctx context.Context, name string) (string, error) {
+	current, err := secretsmanager.Current(ctx, o.client, o.namespace, name)
+	if err != nil {
+		return "", err
 	}
-
-	return yaml.Marshal(&encryptionConfig)
+	if current == nil {
+		return name, nil
+	}
+	return current.Name, nil
 }
 
 func (o *operation) emptySecret(name string) *corev1.Secret {
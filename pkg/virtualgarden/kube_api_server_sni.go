@@ -0,0 +1,122 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+	"fmt"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+)
+
+const (
+	sniVolumeNamePrefix      = "sni-tls"
+	sniVolumeMountPathPrefix = "/srv/kubernetes/sni-tls"
+
+	defaultSNICertManagerIssuerKind  = "Issuer"
+	defaultSNICertManagerIssuerGroup = "cert-manager.io"
+)
+
+func sniVolumeName(index int) string {
+	return fmt.Sprintf("%s-%d", sniVolumeNamePrefix, index)
+}
+
+func sniVolumeMountPath(index int) string {
+	return fmt.Sprintf("%s-%d", sniVolumeMountPathPrefix, index)
+}
+
+// getSNIEntries returns the configured SNI certificates for the kube-apiserver.
+func (o *operation) getSNIEntries() []api.SNIEntry {
+	return o.imports.VirtualGarden.KubeAPIServer.SNI
+}
+
+// getSNIChecksumKeys returns the checksum-annotation keys for every SNI entry's secret, so rotating an SNI
+// certificate (whether provided out-of-band or by cert-manager) triggers a kube-apiserver rollout.
+func (o *operation) getSNIChecksumKeys() []string {
+	entries := o.getSNIEntries()
+	keys := make([]string, 0, len(entries))
+	for _, sni := range entries {
+		keys = append(keys, "checksum/secret-"+sni.SecretName)
+	}
+	return keys
+}
+
+// deploySNICertificates reconciles a cert-manager.io Certificate for every SNI entry that configures CertManager.
+func (o *operation) deploySNICertificates(ctx context.Context) error {
+	for _, sni := range o.getSNIEntries() {
+		if sni.CertManager == nil {
+			continue
+		}
+
+		dnsNames := sni.CertManager.DNSNames
+		if len(dnsNames) == 0 {
+			dnsNames = sni.Hostnames
+		}
+
+		issuerKind := sni.CertManager.IssuerRef.Kind
+		if issuerKind == "" {
+			issuerKind = defaultSNICertManagerIssuerKind
+		}
+		issuerGroup := sni.CertManager.IssuerRef.Group
+		if issuerGroup == "" {
+			issuerGroup = defaultSNICertManagerIssuerGroup
+		}
+
+		certificate := o.emptySNICertificate(sni.SecretName)
+		_, err := controllerutil.CreateOrUpdate(ctx, o.client, certificate, func() error {
+			certificate.Spec = certmanagerv1.CertificateSpec{
+				SecretName: sni.SecretName,
+				DNSNames:   dnsNames,
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  sni.CertManager.IssuerRef.Name,
+					Kind:  issuerKind,
+					Group: issuerGroup,
+				},
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("could not reconcile SNI certificate for secret %q: %w", sni.SecretName, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteSNICertificates deletes the cert-manager.io Certificates reconciled by deploySNICertificates.
+func (o *operation) deleteSNICertificates(ctx context.Context) error {
+	for _, sni := range o.getSNIEntries() {
+		if sni.CertManager == nil {
+			continue
+		}
+
+		certificate := o.emptySNICertificate(sni.SecretName)
+		if err := o.client.Delete(ctx, certificate); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *operation) emptySNICertificate(secretName string) *certmanagerv1.Certificate {
+	return &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: o.namespace}}
+}
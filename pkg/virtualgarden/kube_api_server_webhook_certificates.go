@@ -0,0 +1,115 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/webhookcerts"
+)
+
+const (
+	KubeApiServerSecretNameWebhookCA                = Prefix + "-kube-apiserver-webhook-ca"
+	kubeApiServerSecretNameWebhookCertValidating    = Prefix + "-kube-apiserver-webhook-cert-validating"
+	kubeApiServerSecretNameWebhookCertMutating      = Prefix + "-kube-apiserver-webhook-cert-mutating"
+	kubeApiServerWebhookClientNameValidatingWebhook = "gardener-apiserver-validating-webhook-client"
+	kubeApiServerWebhookClientNameMutatingWebhook   = "gardener-apiserver-mutating-webhook-client"
+)
+
+//go:embed resources/validating-webhook-kubeconfig.yaml
+var validatingWebhookKubeconfigTemplateSource string
+
+//go:embed resources/mutating-webhook-kubeconfig.yaml
+var mutatingWebhookKubeconfigTemplateSource string
+
+var (
+	validatingWebhookKubeconfigTemplate = template.Must(template.New("validating-webhook-kubeconfig").Parse(validatingWebhookKubeconfigTemplateSource))
+	mutatingWebhookKubeconfigTemplate   = template.Must(template.New("mutating-webhook-kubeconfig").Parse(mutatingWebhookKubeconfigTemplateSource))
+)
+
+// webhookKubeconfigData is the template data substituted into the embedded kubeconfig templates.
+type webhookKubeconfigData struct {
+	CABundle          string
+	Server            string
+	ClientCertificate string
+	ClientKey         string
+}
+
+// webhookCertificateRenewalWindow returns how long before expiry the self-signed webhook CA/serving certificates
+// are rotated, per imports.VirtualGarden.KubeAPIServer.WebhookCertificateRenewalWindow, defaulting to
+// webhookcerts.DefaultRenewalWindow.
+func (o *operation) webhookCertificateRenewalWindow() time.Duration {
+	window := o.imports.VirtualGarden.KubeAPIServer.WebhookCertificateRenewalWindow
+	if window == nil || *window == "" {
+		return webhookcerts.DefaultRenewalWindow
+	}
+
+	parsed, err := time.ParseDuration(*window)
+	if err != nil {
+		return webhookcerts.DefaultRenewalWindow
+	}
+
+	return parsed
+}
+
+// renderWebhookKubeconfig ensures (generating or rotating as needed) a serving certificate named clientName signed
+// by ca, and renders tpl with it plus webhook.Server substituted in. caRotated must be set whenever ca was just
+// (re)generated during this reconcile, so every leaf signed by it is force-rotated rather than only the first one
+// this function happens to be called for.
+func (o *operation) renderWebhookKubeconfig(ctx context.Context, tpl *template.Template, leafSecretName, clientName string, webhook api.GardenerControlplaneWebhook, ca *webhookcerts.CA, caRotated bool) ([]byte, error) {
+	bundle, err := webhookcerts.EnsureLeaf(ctx, o.client, o.namespace, leafSecretName, []string{clientName}, ca, caRotated, o.webhookCertificateRenewalWindow())
+	if err != nil {
+		return nil, err
+	}
+
+	data := webhookKubeconfigData{
+		CABundle:          base64.StdEncoding.EncodeToString(bundle.CACertificate),
+		Server:            webhook.Server,
+		ClientCertificate: base64.StdEncoding.EncodeToString(bundle.Certificate),
+		ClientKey:         base64.StdEncoding.EncodeToString(bundle.Key),
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("could not render webhook kubeconfig: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deleteKubeAPIServerWebhookCertificates deletes the shared webhook CA and both leaf certificate secrets. Unlike
+// the secretsmanager-managed secrets, these are not versioned, so they are deleted directly by name.
+func (o *operation) deleteKubeAPIServerWebhookCertificates(ctx context.Context) error {
+	for _, name := range []string{
+		KubeApiServerSecretNameWebhookCA,
+		kubeApiServerSecretNameWebhookCertValidating,
+		kubeApiServerSecretNameWebhookCertMutating,
+	} {
+		if err := client.IgnoreNotFound(o.client.Delete(ctx, o.emptySecret(name))); err != nil {
+			return fmt.Errorf("could not delete secret %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
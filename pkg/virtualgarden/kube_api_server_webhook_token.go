@@ -0,0 +1,55 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+)
+
+// rewriteKubeconfigWithTokenFile rewrites the first user entry of a raw kubeconfig so it authenticates via the
+// given tokenFile path (the mount point of a projected, audience-bound service account token) instead of
+// whatever static credential (token, client certificate, basic auth) it originally carried. It is used to let
+// operators configure the audit webhook backend and the OIDC authentication webhook with short-lived tokens
+// without hand-rolling the kubeconfig themselves. The seed-authorizer (authorization) webhook kubeconfig is not
+// rewritten.
+func rewriteKubeconfigWithTokenFile(rawKubeconfig string, tokenFile string) (string, error) {
+	var kubeconfig map[string]interface{}
+	if err := yaml.Unmarshal([]byte(rawKubeconfig), &kubeconfig); err != nil {
+		return "", fmt.Errorf("could not parse kubeconfig: %w", err)
+	}
+
+	users, ok := kubeconfig["users"].([]interface{})
+	if !ok || len(users) == 0 {
+		return "", fmt.Errorf("kubeconfig does not contain a users entry")
+	}
+
+	user, ok := users[0].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("kubeconfig's first users entry is malformed")
+	}
+
+	user["user"] = map[string]interface{}{"tokenFile": tokenFile}
+	users[0] = user
+	kubeconfig["users"] = users
+
+	rewritten, err := yaml.Marshal(kubeconfig)
+	if err != nil {
+		return "", fmt.Errorf("could not render rewritten kubeconfig: %w", err)
+	}
+
+	return string(rewritten), nil
+}
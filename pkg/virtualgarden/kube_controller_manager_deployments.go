@@ -0,0 +1,276 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/virtual-garden/pkg/api"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/access"
+)
+
+const (
+	KubeControllerManagerServiceName          = Prefix + "-kube-controller-manager"
+	KubeControllerManagerSecretNameKubeconfig = Prefix + "-kube-controller-manager-kubeconfig"
+	KubeControllerManagerClusterRoleName      = Prefix + "-kube-controller-manager"
+
+	KubeControllerManagerPodDisruptionBudgetName   = Prefix + "-kube-controller-manager"
+	KubeControllerManagerVerticalPodAutoscalerName = Prefix + "-kube-controller-manager"
+
+	// ChecksumKeyKubeControllerManagerKubeconfig is the checksums map key for the kube-controller-manager's
+	// kubeconfig secret, so the deployment rolls whenever the token it carries is regenerated.
+	ChecksumKeyKubeControllerManagerKubeconfig = "checksum/secret-" + KubeControllerManagerSecretNameKubeconfig
+
+	kubeControllerManagerContainerName        = "kube-controller-manager"
+	volumeNameKubeControllerManagerKubeconfig = "kubeconfig"
+	volumeNameKubeControllerManagerClientCA   = "ca-client"
+)
+
+func (o *operation) deleteKubeControllerManagerSecrets(ctx context.Context) error {
+	req, err := o.virtualGardenAccessRequest(ctx, KubeControllerManagerSecretNameKubeconfig, []string{KubeControllerManagerClusterRoleName})
+	if err != nil {
+		return err
+	}
+
+	if err := access.Delete(ctx, o.client, o.virtualGardenClient, req); err != nil {
+		return err
+	}
+
+	clusterRole := o.emptyKubeControllerManagerClusterRole()
+	return client.IgnoreNotFound(o.virtualGardenClient.Delete(ctx, clusterRole))
+}
+
+func kubeControllerManagerLabels() map[string]string {
+	return map[string]string{
+		LabelKeyApp:       Prefix,
+		LabelKeyComponent: "kube-controller-manager",
+	}
+}
+
+func (o *operation) deployKubeControllerManagerDeployment(ctx context.Context, checksums map[string]string) error {
+	o.log.Infof("Deploying deployment %s", KubeAPIServerDeploymentNameControllerManager)
+
+	deployment := o.emptyDeployment(KubeAPIServerDeploymentNameControllerManager)
+
+	controllerManagerImports := o.imports.VirtualGarden.KubeControllerManager
+	replicas := pointer.Int32Ptr(1)
+	if controllerManagerImports != nil {
+		replicas = pointer.Int32Ptr(int32(controllerManagerImports.Replicas))
+	}
+
+	annotations := o.addChecksumsToAnnotations(checksums, []string{
+		ChecksumKeyKubeControllerManagerKubeconfig,
+		ChecksumKeyKubeAPIServerCA,
+	})
+
+	command := o.getKubeControllerManagerCommand()
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.client, deployment, func() error {
+		deployment.ObjectMeta.Labels = kubeControllerManagerLabels()
+
+		deployment.Spec = appsv1.DeploymentSpec{
+			RevisionHistoryLimit: pointer.Int32Ptr(0),
+			Replicas:             replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: kubeControllerManagerLabels(),
+			},
+
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: annotations,
+					Labels: map[string]string{
+						LabelKeyApp:                            Prefix,
+						LabelKeyComponent:                      "kube-controller-manager",
+						"networking.gardener.cloud/to-dns":     LabelValueAllowed,
+						"networking.gardener.cloud/to-ingress": LabelValueAllowed,
+						"networking.gardener.cloud/to-world":   LabelValueAllowed,
+					},
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: pointer.BoolPtr(false),
+					ServiceAccountName:           KubeControllerManagerServiceName,
+					PriorityClassName:            o.imports.VirtualGarden.PriorityClassName,
+					Containers: []corev1.Container{
+						{
+							Name:            kubeControllerManagerContainerName,
+							Image:           o.imageRefs.KubeControllerManagerImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         command,
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path:   "/healthz",
+										Port:   intstr.IntOrString{Type: intstr.Int, IntVal: 10257},
+										Scheme: corev1.URISchemeHTTPS,
+									},
+								},
+								InitialDelaySeconds: 15,
+								TimeoutSeconds:      15,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								FailureThreshold:    2,
+							},
+							TerminationMessagePath:   "/dev/termination-log",
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							Resources: corev1.ResourceRequirements{
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("750m"),
+									corev1.ResourceMemory: resource.MustParse("512Mi"),
+								},
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("50m"),
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      volumeNameKubeControllerManagerKubeconfig,
+									MountPath: "/var/lib/kube-controller-manager",
+								},
+								{
+									Name:      volumeNameKubeControllerManagerClientCA,
+									MountPath: "/srv/kubernetes/ca",
+								},
+							},
+						},
+					},
+					DNSPolicy:                     corev1.DNSClusterFirst,
+					RestartPolicy:                 corev1.RestartPolicyAlways,
+					TerminationGracePeriodSeconds: pointer.Int64Ptr(30),
+					Volumes: []corev1.Volume{
+						volumeWithSecretSource(volumeNameKubeControllerManagerKubeconfig, KubeControllerManagerSecretNameKubeconfig),
+						volumeWithSecretSource(volumeNameKubeControllerManagerClientCA, KubeApiServerSecretNameApiServerCACertificate),
+					},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var policy *api.WorkloadPolicy
+	if controllerManagerImports != nil {
+		policy = controllerManagerImports.WorkloadPolicy
+	}
+
+	return o.ensureWorkloadPolicies(ctx, deployment, workloadPolicyOptions(
+		KubeControllerManagerPodDisruptionBudgetName,
+		KubeControllerManagerVerticalPodAutoscalerName,
+		kubeControllerManagerLabels(),
+		policy,
+	))
+}
+
+// getKubeControllerManagerCommand renders the kube-controller-manager command line, applying the same
+// ExtraArgs/FeatureGates passthrough used for the kube-apiserver.
+func (o *operation) getKubeControllerManagerCommand() []string {
+	command := []string{"/usr/local/bin/kube-controller-manager"}
+	command = append(command, "--authentication-kubeconfig=/var/lib/kube-controller-manager/kubeconfig")
+	command = append(command, "--authorization-kubeconfig=/var/lib/kube-controller-manager/kubeconfig")
+	command = append(command, "--kubeconfig=/var/lib/kube-controller-manager/kubeconfig")
+	command = append(command, "--cluster-signing-cert-file=/srv/kubernetes/ca/ca.crt")
+	command = append(command, "--cluster-signing-key-file=/srv/kubernetes/ca/ca.key")
+	command = append(command, "--controllers=*,bootstrapsigner,tokencleaner")
+	command = append(command, "--horizontal-pod-autoscaler-sync-period=30s")
+	command = append(command, "--leader-elect=true")
+	command = append(command, "--node-monitor-grace-period=40s")
+	command = append(command, "--pod-eviction-timeout=2m")
+	command = append(command, "--root-ca-file=/srv/kubernetes/ca/ca.crt")
+	command = append(command, "--service-account-private-key-file=/srv/kubernetes/service-account-key/service_account.key")
+	command = append(command, "--use-service-account-credentials=true")
+	command = append(command, "--v=2")
+
+	if flag := o.getKubeControllerManagerFeatureGatesFlag(); flag != "" {
+		command = append(command, flag)
+	}
+
+	var extraArgs map[string]string
+	if o.imports.VirtualGarden.KubeControllerManager != nil {
+		extraArgs = o.imports.VirtualGarden.KubeControllerManager.ExtraArgs
+	}
+
+	return mergeExtraArgs(command, extraArgs)
+}
+
+func (o *operation) getKubeControllerManagerFeatureGatesFlag() string {
+	if o.imports.VirtualGarden.KubeControllerManager == nil {
+		return ""
+	}
+	return featureGatesFlag(o.imports.VirtualGarden.KubeControllerManager.FeatureGates)
+}
+
+func (o *operation) emptyKubeControllerManagerClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: KubeControllerManagerClusterRoleName}}
+}
+
+// deployKubeControllerManagerClusterRole reconciles the virtual garden ClusterRole that
+// deployKubeControllerManagerSecretKubeconfig binds kube-controller-manager's access identity to.
+func (o *operation) deployKubeControllerManagerClusterRole(ctx context.Context) error {
+	clusterRole := o.emptyKubeControllerManagerClusterRole()
+
+	_, err := controllerutil.CreateOrUpdate(ctx, o.virtualGardenClient, clusterRole, func() error {
+		clusterRole.Rules = []rbacv1.PolicyRule{
+			{
+				// kube-controller-manager runs with --controllers=*, so it needs full access to the built-in API
+				// groups its core controllers manage, but not to CRDs like the Gardener APIs it never touches.
+				APIGroups: []string{"", "apps", "batch", "extensions", "policy", "storage.k8s.io", "networking.k8s.io", "certificates.k8s.io", "coordination.k8s.io"},
+				Resources: []string{"*"},
+				Verbs:     []string{"*"},
+			},
+			{
+				APIGroups: []string{"authentication.k8s.io"},
+				Resources: []string{"tokenreviews"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+		}
+		return nil
+	})
+
+	return err
+}
+
+// deployKubeControllerManagerSecretKubeconfig provisions kube-controller-manager's virtual garden access identity
+// and kubeconfig via the access package: a ServiceAccount and ClusterRoleBinding in the virtual garden, backed by a
+// TokenRequest-minted token the virtual garden kube-apiserver can actually validate.
+func (o *operation) deployKubeControllerManagerSecretKubeconfig(ctx context.Context) error {
+	if err := o.deployKubeControllerManagerClusterRole(ctx); err != nil {
+		return err
+	}
+
+	req, err := o.virtualGardenAccessRequest(ctx, KubeControllerManagerSecretNameKubeconfig, []string{KubeControllerManagerClusterRoleName})
+	if err != nil {
+		return err
+	}
+
+	_, err = access.Reconcile(ctx, o.client, o.virtualGardenClient, req)
+	return err
+}
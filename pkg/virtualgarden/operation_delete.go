@@ -29,6 +29,26 @@ func (o *operation) Delete(ctx context.Context) error {
 			Name: "Deleting the service for exposing the virtual garden kube-apiserver",
 			Fn:   o.DeleteKubeAPIServerService,
 		})
+		deleteKubeAPIServerSecrets = graph.Add(flow.Task{
+			Name: "Deleting the secrets of the virtual garden kube-apiserver",
+			Fn:   o.deleteKubeAPIServerSecrets,
+		})
+		deleteGardenerControllerManager = graph.Add(flow.Task{
+			Name: "Deleting the gardener-controller-manager",
+			Fn:   o.DeleteGardenerControllerManager,
+		})
+		deleteDeployments = graph.Add(flow.Task{
+			Name: "Deleting the kube-apiserver and kube-controller-manager deployments",
+			Fn:   o.deleteDeployments,
+		})
+		deleteKubeControllerManagerSecrets = graph.Add(flow.Task{
+			Name: "Deleting the secrets of the kube-controller-manager",
+			Fn:   o.deleteKubeControllerManagerSecrets,
+		})
+		deleteSNICertificates = graph.Add(flow.Task{
+			Name: "Deleting the SNI certificates of the virtual garden kube-apiserver",
+			Fn:   o.deleteSNICertificates,
+		})
 		deleteETCD = graph.Add(flow.Task{
 			Name: "Deleting the main and events etcds",
 			Fn:   o.DeleteETCD,
@@ -39,9 +59,10 @@ func (o *operation) Delete(ctx context.Context) error {
 			Dependencies: flow.NewTaskIDs(deleteETCD),
 		})
 		_ = graph.Add(flow.Task{
-			Name:         "Deleting namespace for virtual-garden deployment in hosting cluster",
-			Fn:           flow.TaskFn(o.DeleteNamespace).SkipIf(!o.handleNamespace),
-			Dependencies: flow.NewTaskIDs(deleteKubeAPIServerService, deleteETCD, deleteBackupBucket),
+			Name: "Deleting namespace for virtual-garden deployment in hosting cluster",
+			Fn:   flow.TaskFn(o.DeleteNamespace).SkipIf(!o.handleNamespace),
+			Dependencies: flow.NewTaskIDs(deleteKubeAPIServerService, deleteKubeAPIServerSecrets, deleteGardenerControllerManager,
+				deleteDeployments, deleteKubeControllerManagerSecrets, deleteSNICertificates, deleteETCD, deleteBackupBucket),
 		})
 	)
 
@@ -0,0 +1,447 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	configv1 "k8s.io/apiserver/pkg/apis/config/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/gardener/gardener/pkg/utils/flow"
+	"github.com/gardener/virtual-garden/pkg/util"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/secretsmanager"
+)
+
+const (
+	// rotationStateConfigMapName persists RotateEncryption's progress so the flow is resumable across controller
+	// restarts, instead of restarting the whole online re-encryption dance from scratch.
+	rotationStateConfigMapName = Prefix + "-kube-apiserver-encryption-rotation"
+	rotationStateDataKey       = "state"
+
+	rotationDeploymentPollInterval = 5 * time.Second
+	rotationDeploymentPollTimeout  = 10 * time.Minute
+)
+
+// encryptionRotationPhase identifies how far RotateEncryption has progressed through the online re-encryption
+// dance described on encryptionRotationState.
+type encryptionRotationPhase string
+
+const (
+	encryptionRotationPhaseKeyAdded    encryptionRotationPhase = "KeyAdded"
+	encryptionRotationPhaseRestarted   encryptionRotationPhase = "Restarted"
+	encryptionRotationPhaseReencrypted encryptionRotationPhase = "Reencrypted"
+	encryptionRotationPhaseKeysRemoved encryptionRotationPhase = "KeysRemoved"
+)
+
+// encryptionRotationState is persisted as JSON in rotationStateConfigMapName. An empty Phase means no rotation is
+// in progress.
+type encryptionRotationState struct {
+	// Phase is the last step of the dance that completed successfully.
+	Phase encryptionRotationPhase `json:"phase,omitempty"`
+	// RotationSeed is the secretsmanager rotation seed used for the new encryption-config secret version created
+	// by this rotation, derived from RetryCount so retrying after a failed attempt reuses (rather than
+	// re-creates) whatever progress already exists for it.
+	RotationSeed string `json:"rotationSeed,omitempty"`
+	// ListContinue is the List continuation token of the last page of resources successfully re-encrypted.
+	// rotationReencryptResources clears it before sweeping each resource kind, so a restart during the
+	// re-encryption phase sweeps every kind again from the beginning.
+	ListContinue string `json:"listContinue,omitempty"`
+	// ListResourceVersion records the resourceVersion of the first page listed for the resource kind being swept.
+	// It is not passed back to List, so later pages are not pinned to that snapshot.
+	ListResourceVersion string `json:"listResourceVersion,omitempty"`
+	// RetryCount is incremented every time RotateEncryption is invoked while a rotation is already in progress.
+	RetryCount int `json:"retryCount,omitempty"`
+	// CompletedRotateNow records that the rotation requested by the last observed RotateNow=true has already run
+	// to completion, so re-reconciling while RotateNow is still true (the normal state right after a rotation
+	// request, since nothing clears it) does not start another rotation. It is cleared as soon as RotateNow is
+	// observed false again, so a genuine new request (false -> true) still triggers a rotation.
+	CompletedRotateNow bool `json:"completedRotateNow,omitempty"`
+}
+
+// RotateEncryption runs the online etcd-encryption-key rotation flow: a fresh AESCBC key is added to the
+// kube-apiserver's encryption config, the kube-apiserver is restarted so it is serving with both old and new keys,
+// every resource covered by the encryption config is re-written so etcd stores it under the new key, the retired
+// keys are then dropped from the config, and the kube-apiserver is restarted once more. It is a no-op unless
+// imports.VirtualGarden.KubeAPIServer.EncryptionConfig.RotateNow is set or a previous invocation left a rotation
+// in progress, and it is safe to call repeatedly (including across controller restarts and while RotateNow stays
+// true after completion): progress is persisted in rotationStateConfigMapName and each step is skipped once it has
+// already completed.
+func (o *operation) RotateEncryption(ctx context.Context) error {
+	state, err := o.readEncryptionRotationState(ctx)
+	if err != nil {
+		return err
+	}
+
+	encryptionConfig := o.getEncryptionConfig()
+
+	if state.Phase == "" {
+		if !encryptionConfig.RotateNow {
+			if state.CompletedRotateNow {
+				state.CompletedRotateNow = false
+				return o.writeEncryptionRotationState(ctx, state)
+			}
+			return nil
+		}
+		if state.CompletedRotateNow {
+			return nil
+		}
+	}
+
+	if state.Phase != "" {
+		state.RetryCount++
+		if err := o.writeEncryptionRotationState(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	var (
+		graph = flow.NewGraph("Virtual Garden Kube-Apiserver Encryption Key Rotation")
+
+		addKey = graph.Add(flow.Task{
+			Name: "Adding a new encryption key to the kube-apiserver encryption config",
+			Fn:   flow.TaskFn(o.rotationAddKey).SkipIf(state.Phase != ""),
+		})
+		restartAfterAdd = graph.Add(flow.Task{
+			Name:         "Restarting the kube-apiserver to serve with the new encryption key",
+			Fn:           flow.TaskFn(o.rotationRestartKubeAPIServer).SkipIf(rotationPhaseAtLeast(state.Phase, encryptionRotationPhaseRestarted)),
+			Dependencies: flow.NewTaskIDs(addKey),
+		})
+		reencrypt = graph.Add(flow.Task{
+			Name:         "Re-encrypting existing resources with the new encryption key",
+			Fn:           flow.TaskFn(o.rotationReencryptResources).SkipIf(rotationPhaseAtLeast(state.Phase, encryptionRotationPhaseReencrypted)),
+			Dependencies: flow.NewTaskIDs(restartAfterAdd),
+		})
+		removeKeys = graph.Add(flow.Task{
+			Name:         "Removing retired encryption keys from the kube-apiserver encryption config",
+			Fn:           flow.TaskFn(o.rotationRemoveRetiredKeys).SkipIf(rotationPhaseAtLeast(state.Phase, encryptionRotationPhaseKeysRemoved)),
+			Dependencies: flow.NewTaskIDs(reencrypt),
+		})
+		_ = graph.Add(flow.Task{
+			Name:         "Restarting the kube-apiserver to drop the retired encryption keys",
+			Fn:           o.rotationFinish,
+			Dependencies: flow.NewTaskIDs(removeKeys),
+		})
+	)
+
+	return graph.Compile().Run(flow.Opts{
+		Context:          ctx,
+		Logger:           o.log,
+		ProgressReporter: flow.NewImmediateProgressReporter(o.progressReporter),
+	})
+}
+
+// rotationPhaseAtLeast reports whether current has reached or passed target in the rotation's fixed phase order,
+// so a task can be skipped once an earlier attempt already got it (and everything after it) done.
+func rotationPhaseAtLeast(current, target encryptionRotationPhase) bool {
+	order := map[encryptionRotationPhase]int{
+		"":                                 0,
+		encryptionRotationPhaseKeyAdded:    1,
+		encryptionRotationPhaseRestarted:   2,
+		encryptionRotationPhaseReencrypted: 3,
+		encryptionRotationPhaseKeysRemoved: 4,
+	}
+	return order[current] >= order[target]
+}
+
+// rotationAddKey reads the current encryption-config secret, prepends a freshly generated key to every
+// resource's key-based (aescbc/aesgcm/secretbox) provider while keeping the existing key(s) for decryption, and persists the result as a new
+// secretsmanager version so the in-progress and pre-rotation configs both remain addressable.
+func (o *operation) rotationAddKey(ctx context.Context) error {
+	state, err := o.readEncryptionRotationState(ctx)
+	if err != nil {
+		return err
+	}
+
+	state.RotationSeed = fmt.Sprintf("online-rotation-%d", state.RetryCount)
+
+	current, err := o.currentEncryptionConfigSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	var existing []byte
+	if current != nil {
+		existing = current.Data[encryptionConfigSecretKey]
+	}
+
+	rendered, err := o.generateEncryptionConfig(existing)
+	if err != nil {
+		return err
+	}
+
+	if _, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:         KubeApiServerSecretNameEncryptionConfig,
+		RotationSeed: state.RotationSeed,
+		GracePeriod:  o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{encryptionConfigSecretKey: rendered}, nil
+		},
+	}); err != nil {
+		return err
+	}
+
+	state.Phase = encryptionRotationPhaseKeyAdded
+	return o.writeEncryptionRotationState(ctx, state)
+}
+
+// rotationRestartKubeAPIServer triggers a rollout of the kube-apiserver deployment (so it picks up whichever
+// encryption-config secret version is now current) and waits for every replica to become ready.
+func (o *operation) rotationRestartKubeAPIServer(ctx context.Context) error {
+	deployment := o.emptyDeployment(KubeAPIServerDeploymentNameAPIServer)
+	if _, err := controllerutil.CreateOrUpdate(ctx, o.client, deployment, func() error {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations["virtual-garden.gardener.cloud/restartedAt"] = time.Now().Format(time.RFC3339)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("could not restart deployment %q: %w", KubeAPIServerDeploymentNameAPIServer, err)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, rotationDeploymentPollInterval, rotationDeploymentPollTimeout, true, func(ctx context.Context) (bool, error) {
+		current := o.emptyDeployment(KubeAPIServerDeploymentNameAPIServer)
+		if err := o.client.Get(ctx, util.GetKey(current), current); err != nil {
+			return false, err
+		}
+		return deploymentRolledOut(current), nil
+	}); err != nil {
+		return fmt.Errorf("kube-apiserver did not become ready after restart: %w", err)
+	}
+
+	state, err := o.readEncryptionRotationState(ctx)
+	if err != nil {
+		return err
+	}
+	state.Phase = encryptionRotationPhaseRestarted
+	return o.writeEncryptionRotationState(ctx, state)
+}
+
+// deploymentRolledOut reports whether every desired replica of deployment has been updated and is ready.
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	var desired int32 = 1
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == desired &&
+		deployment.Status.ReadyReplicas == desired
+}
+
+// rotationReencryptResources lists every resource named in the encryption config's Resources (the virtual garden
+// kube-apiserver's own resources, via o.virtualGardenClient, not the hosting cluster) across all namespaces and
+// issues a no-op update on each, so etcd rewrites it under the new encryption key. Each page's continuation
+// token is persisted in state.ListContinue, but it is cleared before each resource kind is swept, so an
+// interrupted sweep starts over from the beginning rather than resuming.
+func (o *operation) rotationReencryptResources(ctx context.Context) error {
+	state, err := o.readEncryptionRotationState(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range o.encryptionRotationResourceKinds() {
+		state.ListContinue = ""
+		state.ListResourceVersion = ""
+
+		for {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk)
+
+			opts := []client.ListOption{client.Limit(100)}
+			if state.ListContinue != "" {
+				opts = append(opts, client.Continue(state.ListContinue))
+			}
+
+			if err := o.virtualGardenClient.List(ctx, list, opts...); err != nil {
+				return fmt.Errorf("could not list %s for re-encryption: %w", gvk.Kind, err)
+			}
+
+			if state.ListResourceVersion == "" {
+				state.ListResourceVersion = list.GetResourceVersion()
+			}
+
+			for i := range list.Items {
+				item := &list.Items[i]
+				if err := o.virtualGardenClient.Update(ctx, item); err != nil {
+					return fmt.Errorf("could not re-encrypt %s %s/%s: %w", gvk.Kind, item.GetNamespace(), item.GetName(), err)
+				}
+			}
+
+			state.ListContinue = list.GetContinue()
+			if err := o.writeEncryptionRotationState(ctx, state); err != nil {
+				return err
+			}
+
+			if state.ListContinue == "" {
+				break
+			}
+		}
+	}
+
+	state.Phase = encryptionRotationPhaseReencrypted
+	return o.writeEncryptionRotationState(ctx, state)
+}
+
+// encryptionRotationResourceKinds returns the GroupVersionKinds of every resource named in the encryption config's
+// Resources, defaulting to core/v1 Secrets to match getEncryptionConfig's default.
+func (o *operation) encryptionRotationResourceKinds() []schema.GroupVersionKind {
+	var kinds []schema.GroupVersionKind
+	for _, resourceConfig := range o.getEncryptionConfig().Resources {
+		for _, resource := range resourceConfig.Resources {
+			kinds = append(kinds, schema.GroupVersionKind{Version: "v1", Kind: resourceKind(resource)})
+		}
+	}
+	return kinds
+}
+
+// resourceKind maps a plural lower-case core/v1 resource name (e.g. "secrets") to the Kind of its list (e.g.
+// "SecretList"), as set on the UnstructuredList that rotationReencryptResources lists. Only "secrets" and
+// "configmaps" are mapped; any other name is returned unchanged.
+func resourceKind(resource string) string {
+	switch resource {
+	case "secrets":
+		return "SecretList"
+	case "configmaps":
+		return "ConfigMapList"
+	default:
+		return resource
+	}
+}
+
+// rotationRemoveRetiredKeys rewrites the encryption-config secret so every key-based provider only lists its
+// newest (the just-added) key, dropping the retired ones now that every resource has been re-encrypted with it.
+func (o *operation) rotationRemoveRetiredKeys(ctx context.Context) error {
+	current, err := o.currentEncryptionConfigSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("encryption config secret %q not found", KubeApiServerSecretNameEncryptionConfig)
+	}
+
+	var config configv1.EncryptionConfiguration
+	if err := yaml.Unmarshal(current.Data[encryptionConfigSecretKey], &config); err != nil {
+		return fmt.Errorf("could not parse encryption config: %w", err)
+	}
+
+	for i, resource := range config.Resources {
+		for j, provider := range resource.Providers {
+			switch {
+			case provider.AESCBC != nil && len(provider.AESCBC.Keys) > 1:
+				config.Resources[i].Providers[j].AESCBC.Keys = provider.AESCBC.Keys[:1]
+			case provider.AESGCM != nil && len(provider.AESGCM.Keys) > 1:
+				config.Resources[i].Providers[j].AESGCM.Keys = provider.AESGCM.Keys[:1]
+			case provider.Secretbox != nil && len(provider.Secretbox.Keys) > 1:
+				config.Resources[i].Providers[j].Secretbox.Keys = provider.Secretbox.Keys[:1]
+			}
+		}
+	}
+
+	rendered, err := yaml.Marshal(&config)
+	if err != nil {
+		return err
+	}
+
+	state, err := o.readEncryptionRotationState(ctx)
+	if err != nil {
+		return err
+	}
+	state.RotationSeed = fmt.Sprintf("online-rotation-%d-trimmed", state.RetryCount)
+
+	if _, err := secretsmanager.Generate(ctx, o.client, o.namespace, secretsmanager.Config{
+		Name:         KubeApiServerSecretNameEncryptionConfig,
+		RotationSeed: state.RotationSeed,
+		GracePeriod:  o.rotationGracePeriod(),
+		Generate: func(map[string][]byte) (map[string][]byte, error) {
+			return map[string][]byte{encryptionConfigSecretKey: rendered}, nil
+		},
+	}); err != nil {
+		return err
+	}
+
+	state.Phase = encryptionRotationPhaseKeysRemoved
+	return o.writeEncryptionRotationState(ctx, state)
+}
+
+// rotationFinish restarts the kube-apiserver once more so it stops accepting the now-dropped retired keys, then
+// resets the persisted rotation state to a fresh, completed state: CompletedRotateNow is recorded so reconciling
+// again while RotateNow is still true (the normal state right after this dance finishes) does not start another
+// rotation; it is cleared once RotateNow is observed false, so the next genuine request still triggers one.
+func (o *operation) rotationFinish(ctx context.Context) error {
+	if err := o.rotationRestartKubeAPIServer(ctx); err != nil {
+		return err
+	}
+
+	return o.writeEncryptionRotationState(ctx, &encryptionRotationState{CompletedRotateNow: o.getEncryptionConfig().RotateNow})
+}
+
+// currentEncryptionConfigSecret returns the current version of the kube-apiserver encryption-config secret, or
+// nil if none has been generated yet.
+func (o *operation) currentEncryptionConfigSecret(ctx context.Context) (*corev1.Secret, error) {
+	return secretsmanager.Current(ctx, o.client, o.namespace, KubeApiServerSecretNameEncryptionConfig)
+}
+
+// readEncryptionRotationState loads the persisted rotation progress, returning a zero-value (no rotation in
+// progress) state if rotationStateConfigMapName does not exist yet.
+func (o *operation) readEncryptionRotationState(ctx context.Context) (*encryptionRotationState, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := o.client.Get(ctx, client.ObjectKey{Name: rotationStateConfigMapName, Namespace: o.namespace}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &encryptionRotationState{}, nil
+		}
+		return nil, fmt.Errorf("could not read encryption rotation state: %w", err)
+	}
+
+	state := &encryptionRotationState{}
+	if err := json.Unmarshal([]byte(configMap.Data[rotationStateDataKey]), state); err != nil {
+		return nil, fmt.Errorf("could not parse encryption rotation state: %w", err)
+	}
+
+	return state, nil
+}
+
+// writeEncryptionRotationState persists state to rotationStateConfigMapName, creating it if necessary.
+func (o *operation) writeEncryptionRotationState(ctx context.Context, state *encryptionRotationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: rotationStateConfigMapName, Namespace: o.namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, o.client, configMap, func() error {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[rotationStateDataKey] = string(data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist encryption rotation state: %w", err)
+	}
+
+	return nil
+}
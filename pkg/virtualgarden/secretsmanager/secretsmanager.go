@@ -0,0 +1,203 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secretsmanager generates versioned, hash-suffixed Secrets, analogous to Gardener's own SecretsManager.
+// Each logical secret is stored as "<name>-<hash>", where hash is derived from the name and a caller-supplied
+// rotation seed. Bumping the rotation seed creates a new version and points the "current" label at it, while the
+// previous version is retained (labeled manager.gardener.cloud/persist=true) for a grace period so in-flight
+// consumers keep working, before Cleanup garbage-collects it.
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/gardener/virtual-garden/pkg/util"
+)
+
+const (
+	// LabelKeyName groups every version of the same logical secret.
+	LabelKeyName = "secret.virtual-garden.gardener.cloud/name"
+	// LabelKeyPersist marks a secret as managed by this package, so Cleanup knows to consider it.
+	LabelKeyPersist = "manager.gardener.cloud/persist"
+	// LabelKeyCurrent marks the version that Current (and downstream deployers) should use.
+	LabelKeyCurrent = "manager.gardener.cloud/current"
+	// AnnotationExpirationTime records when a superseded version becomes eligible for garbage collection.
+	AnnotationExpirationTime = "manager.gardener.cloud/expiration-time"
+
+	// DefaultGracePeriod is used when Config.GracePeriod is zero.
+	DefaultGracePeriod = 24 * time.Hour
+)
+
+// Config describes a logical secret managed by Generate.
+type Config struct {
+	// Name is the logical name shared by every version of this secret.
+	Name string
+	// RotationSeed is bumped by the caller (typically from an imports rotation field) to force a new version.
+	RotationSeed string
+	// GracePeriod is how long a superseded version is kept before Cleanup deletes it. Defaults to
+	// DefaultGracePeriod if zero.
+	GracePeriod time.Duration
+	// Generate computes the new version's data. It is only invoked when that version does not exist yet. previous
+	// holds the current version's data at the time of the call, or nil if no version has been generated before,
+	// so rotation can overlap (e.g. keep a previous encryption key around for decrypt-only).
+	Generate func(previous map[string][]byte) (map[string][]byte, error)
+}
+
+// Generate idempotently creates the secret version named "<config.Name>-<hash>" (hash derived from config.Name and
+// config.RotationSeed) if it does not exist yet, marks it as the current version of config.Name, and schedules any
+// previously-current version for garbage collection after its grace period. It returns the current version.
+func Generate(ctx context.Context, c client.Client, namespace string, config Config) (*corev1.Secret, error) {
+	previous, err := Current(ctx, c, namespace, config.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	name := versionedName(config.Name, config.RotationSeed)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+
+	err = c.Get(ctx, util.GetKey(secret), secret)
+	switch {
+	case err == nil:
+		// version already exists; data is immutable once generated
+	case apierrors.IsNotFound(err):
+		var previousData map[string][]byte
+		if previous != nil {
+			previousData = previous.Data
+		}
+
+		data, genErr := config.Generate(previousData)
+		if genErr != nil {
+			return nil, fmt.Errorf("could not generate secret %q: %w", config.Name, genErr)
+		}
+
+		secret.Labels = map[string]string{LabelKeyName: config.Name}
+		secret.Data = data
+		if err := c.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("could not create secret %q: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("could not get secret %q: %w", name, err)
+	}
+
+	if err := markCurrent(ctx, c, namespace, config, previous, secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// markCurrent points the current label at secret and, if a different version was previously current, labels it
+// persisted and stamps it with an expiration time so Cleanup can reap it once its grace period elapses.
+func markCurrent(ctx context.Context, c client.Client, namespace string, config Config, previous, current *corev1.Secret) error {
+	if current.Labels == nil {
+		current.Labels = map[string]string{}
+	}
+	_, hadExpiration := current.Annotations[AnnotationExpirationTime]
+	if current.Labels[LabelKeyCurrent] != "true" || current.Labels[LabelKeyPersist] != "true" || hadExpiration {
+		patch := client.MergeFrom(current.DeepCopy())
+		current.Labels[LabelKeyName] = config.Name
+		current.Labels[LabelKeyCurrent] = "true"
+		current.Labels[LabelKeyPersist] = "true"
+		// current may be a version that was previously retired (its RotationSeed reproduces an earlier version's
+		// hash) and is now being re-promoted; clear any stale expiration so Cleanup does not reap the live secret.
+		delete(current.Annotations, AnnotationExpirationTime)
+		if err := c.Patch(ctx, current, patch); err != nil {
+			return fmt.Errorf("could not mark secret %q current: %w", current.Name, err)
+		}
+	}
+
+	if previous == nil || previous.Name == current.Name {
+		return nil
+	}
+
+	gracePeriod := config.GracePeriod
+	if gracePeriod == 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+
+	patch := client.MergeFrom(previous.DeepCopy())
+	if previous.Labels == nil {
+		previous.Labels = map[string]string{}
+	}
+	previous.Labels[LabelKeyCurrent] = "false"
+	previous.Labels[LabelKeyPersist] = "true"
+	if previous.Annotations == nil {
+		previous.Annotations = map[string]string{}
+	}
+	previous.Annotations[AnnotationExpirationTime] = time.Now().Add(gracePeriod).Format(time.RFC3339)
+
+	if err := c.Patch(ctx, previous, patch); err != nil {
+		return fmt.Errorf("could not retire superseded secret %q: %w", previous.Name, err)
+	}
+
+	return nil
+}
+
+// Current returns the current version of the logical secret name, or nil if none has been generated yet.
+func Current(ctx context.Context, c client.Client, namespace, name string) (*corev1.Secret, error) {
+	var list corev1.SecretList
+	if err := c.List(ctx, &list, client.InNamespace(namespace), client.MatchingLabels{
+		LabelKeyName:    name,
+		LabelKeyCurrent: "true",
+	}); err != nil {
+		return nil, fmt.Errorf("could not list versions of secret %q: %w", name, err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	return &list.Items[0], nil
+}
+
+// Cleanup deletes every managed secret version in namespace whose AnnotationExpirationTime has passed.
+func Cleanup(ctx context.Context, c client.Client, namespace string) error {
+	var list corev1.SecretList
+	if err := c.List(ctx, &list, client.InNamespace(namespace), client.MatchingLabels{LabelKeyPersist: "true"}); err != nil {
+		return fmt.Errorf("could not list managed secrets: %w", err)
+	}
+
+	for i := range list.Items {
+		secret := &list.Items[i]
+
+		raw, ok := secret.Annotations[AnnotationExpirationTime]
+		if !ok {
+			continue
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil || time.Now().Before(expiresAt) {
+			continue
+		}
+
+		if err := client.IgnoreNotFound(c.Delete(ctx, secret)); err != nil {
+			return fmt.Errorf("could not delete expired secret %q: %w", secret.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func versionedName(name, rotationSeed string) string {
+	hash := utils.ComputeChecksum(map[string]string{"name": name, "rotationSeed": rotationSeed})
+	return fmt.Sprintf("%s-%s", name, hash[:8])
+}
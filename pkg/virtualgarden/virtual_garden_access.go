@@ -0,0 +1,50 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package virtualgarden
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/virtual-garden/pkg/util"
+	"github.com/gardener/virtual-garden/pkg/virtualgarden/access"
+)
+
+// virtualGardenAccessRequest builds the access.AccessRequest for a runtime-cluster component named name, bound to
+// clusterRoles in the virtual garden, pointing at the virtual garden kube-apiserver's in-cluster Service URL and CA.
+func (o *operation) virtualGardenAccessRequest(ctx context.Context, name string, clusterRoles []string) (access.AccessRequest, error) {
+	caBundle, err := o.kubeAPIServerCABundle(ctx)
+	if err != nil {
+		return access.AccessRequest{}, err
+	}
+
+	return access.AccessRequest{
+		Name:         name,
+		Namespace:    o.namespace,
+		ClusterRoles: clusterRoles,
+		ServerURL:    fmt.Sprintf("https://%s.%s.svc:443", KubeAPIServerDeploymentNameAPIServer, o.namespace),
+		CABundle:     caBundle,
+	}, nil
+}
+
+// kubeAPIServerCABundle reads the virtual garden kube-apiserver's CA certificate, so components authenticating
+// against it via access.Reconcile can embed it in their kubeconfig's certificate-authority-data.
+func (o *operation) kubeAPIServerCABundle(ctx context.Context) ([]byte, error) {
+	secret := o.emptySecret(KubeApiServerSecretNameApiServerCACertificate)
+	if err := o.client.Get(ctx, util.GetKey(secret), secret); err != nil {
+		return nil, fmt.Errorf("could not read kube-apiserver CA certificate %q: %w", KubeApiServerSecretNameApiServerCACertificate, err)
+	}
+	return secret.Data["ca.crt"], nil
+}
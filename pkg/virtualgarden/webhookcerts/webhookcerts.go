@@ -0,0 +1,292 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhookcerts generates and rotates a self-signed CA and leaf serving certificates for admission/authentication
+// webhook clients, stored as Secrets. The CA is long-lived and only regenerated once it is itself within its renewal
+// window; leaf certificates are rotated independently (and more often), so a leaf rotation never invalidates trust
+// for peers that still have the previous leaf cached.
+package webhookcerts
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/virtual-garden/pkg/util"
+)
+
+const (
+	keyCACertificate = "ca.crt"
+	keyCAKey         = "ca.key"
+	keyCertificate   = "tls.crt"
+	keyKey           = "tls.key"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 90 * 24 * time.Hour
+
+	// DefaultRenewalWindow is used when EnsureCA or EnsureLeaf is called with a zero renewalWindow: a certificate is rotated once
+	// less than this much of its validity remains.
+	DefaultRenewalWindow = 30 * 24 * time.Hour
+
+	keySize = 2048
+)
+
+// Bundle is a CA certificate plus a leaf certificate signed by it, PEM-encoded.
+type Bundle struct {
+	CACertificate []byte
+	Certificate   []byte
+	Key           []byte
+}
+
+// CA is a parsed CA certificate and key, as ensured by EnsureCA, kept around for signing leaf certificates via
+// EnsureLeaf.
+type CA struct {
+	certificatePEM []byte
+	certificate    *x509.Certificate
+	key            *rsa.PrivateKey
+}
+
+// EnsureCA idempotently loads (creating or rotating as needed) the CA stored in caSecretName in namespace. The CA
+// is rotated only once it is within renewalWindow of its own expiry. A zero renewalWindow defaults to
+// DefaultRenewalWindow. The returned bool reports whether the CA was (re)generated during this call; callers that
+// ensure more than one leaf signed by this CA in the same reconcile must pass it to every EnsureLeaf call, since a
+// leaf issued by a since-discarded CA would stop validating.
+func EnsureCA(ctx context.Context, c client.Client, namespace, caSecretName string, renewalWindow time.Duration) (*CA, bool, error) {
+	if renewalWindow == 0 {
+		renewalWindow = DefaultRenewalWindow
+	}
+
+	certPEM, cert, key, rotated, err := ensureCA(ctx, c, namespace, caSecretName, renewalWindow)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not ensure webhook CA %q: %w", caSecretName, err)
+	}
+
+	return &CA{certificatePEM: certPEM, certificate: cert, key: key}, rotated, nil
+}
+
+// EnsureLeaf idempotently loads (creating or rotating as needed) a leaf certificate for dnsNames stored in
+// leafSecretName in namespace, signed by ca. It is rotated once it is within renewalWindow of its expiry, its DNS
+// names have changed, or caRotated is set (since a leaf signed by a since-discarded CA would stop validating). A
+// zero renewalWindow defaults to DefaultRenewalWindow.
+func EnsureLeaf(ctx context.Context, c client.Client, namespace, leafSecretName string, dnsNames []string, ca *CA, caRotated bool, renewalWindow time.Duration) (*Bundle, error) {
+	if renewalWindow == 0 {
+		renewalWindow = DefaultRenewalWindow
+	}
+
+	certPEM, keyPEM, err := ensureLeaf(ctx, c, namespace, leafSecretName, dnsNames, ca.certificate, ca.key, renewalWindow, caRotated)
+	if err != nil {
+		return nil, fmt.Errorf("could not ensure webhook serving certificate %q: %w", leafSecretName, err)
+	}
+
+	return &Bundle{CACertificate: ca.certificatePEM, Certificate: certPEM, Key: keyPEM}, nil
+}
+
+// ensureCA returns the PEM-encoded CA certificate, the parsed CA certificate/key, creating or rotating the CA
+// secret as needed. The final bool reports whether the CA was (re)generated during this call.
+func ensureCA(ctx context.Context, c client.Client, namespace, name string, renewalWindow time.Duration) ([]byte, *x509.Certificate, *rsa.PrivateKey, bool, error) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	err := c.Get(ctx, util.GetKey(secret), secret)
+	switch {
+	case err == nil:
+		cert, key, parseErr := parseCertificateAndKey(secret.Data[keyCACertificate], secret.Data[keyCAKey])
+		if parseErr == nil && !needsRenewal(cert, renewalWindow) {
+			return secret.Data[keyCACertificate], cert, key, false, nil
+		}
+	case apierrors.IsNotFound(err):
+		// created below
+	default:
+		return nil, nil, nil, false, err
+	}
+
+	cert, certPEM, key, err := generateCA()
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	if err := upsert(ctx, c, secret, map[string][]byte{keyCACertificate: certPEM, keyCAKey: encodeKey(key)}); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	return certPEM, cert, key, true, nil
+}
+
+// ensureLeaf returns the PEM-encoded leaf certificate and private key, creating or rotating the leaf secret as
+// needed. forceRotate is set when the CA was just regenerated.
+func ensureLeaf(ctx context.Context, c client.Client, namespace, name string, dnsNames []string, caCert *x509.Certificate, caKey *rsa.PrivateKey, renewalWindow time.Duration, forceRotate bool) ([]byte, []byte, error) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	err := c.Get(ctx, util.GetKey(secret), secret)
+	switch {
+	case err == nil:
+		if !forceRotate {
+			cert, _, parseErr := parseCertificateAndKey(secret.Data[keyCertificate], secret.Data[keyKey])
+			if parseErr == nil && !needsRenewal(cert, renewalWindow) && sameDNSNames(cert.DNSNames, dnsNames) {
+				return secret.Data[keyCertificate], secret.Data[keyKey], nil
+			}
+		}
+	case apierrors.IsNotFound(err):
+		// created below
+	default:
+		return nil, nil, err
+	}
+
+	certPEM, keyPEM, err := generateLeaf(dnsNames, caCert, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := upsert(ctx, c, secret, map[string][]byte{keyCertificate: certPEM, keyKey: keyPEM}); err != nil {
+		return nil, nil, err
+	}
+
+	return certPEM, keyPEM, nil
+}
+
+func upsert(ctx context.Context, c client.Client, secret *corev1.Secret, data map[string][]byte) error {
+	exists := secret.ResourceVersion != ""
+	secret.Data = data
+	if exists {
+		return c.Update(ctx, secret)
+	}
+	return c.Create(ctx, secret)
+}
+
+func needsRenewal(cert *x509.Certificate, renewalWindow time.Duration) bool {
+	return time.Now().Add(renewalWindow).After(cert.NotAfter)
+}
+
+func sameDNSNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func generateCA() (*x509.Certificate, []byte, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "virtual-garden-webhook-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return cert, encodeCertificate(certDER), key, nil
+}
+
+func generateLeaf(dnsNames []string, caCert *x509.Certificate, caKey *rsa.PrivateKey) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var commonName string
+	if len(dnsNames) > 0 {
+		commonName = dnsNames[0]
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(leafValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encodeCertificate(certDER), encodeKey(key), nil
+}
+
+func parseCertificateAndKey(certPEM, keyPEM []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("no certificate found in PEM data")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("no key found in PEM data")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func randomSerial() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	return rand.Int(rand.Reader, limit)
+}